@@ -0,0 +1,203 @@
+package decnum
+
+import (
+	"math/big"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                     exact sum of many numbers                        */
+/*                                                                      */
+/************************************************************************/
+
+// Accumulator computes the exact sum of Quad numbers, which is rounded only once, by Result.
+//
+// Summing Quads with Add rounds the total at each step as soon as it needs more than 34 digits, so that the result depends on the order of the operands.
+// Accumulator keeps the sum as an integer coefficient of arbitrary size, with the smallest exponent of the operands, so that no rounding occurs
+// before Result is called, and the result is always the same, regardless of the order of the operands.
+//
+//        var acc Accumulator
+//
+//        for _, x := range amounts {
+//                acc.Add(x)
+//        }
+//
+//        total := acc.Result(RoundHalfEven)
+//
+// The coefficient has as many digits as the spread between the largest and the smallest exponent of the operands, plus 34.
+// It is small for usual values, e.g. amounts, but it is only bounded by the range of the exponent:
+// after Add(1E+6144) and Add(1E-6176), it has 12,321 digits, about 5 KB, and each following Add costs an addition on this size,
+// or a multiplication if the operand has a smaller exponent. This keeps the sum exact: Sub(1E+6144) then gives exactly 1E-6176.
+// As the exponents of Quads are between -6176 and 6111, the coefficient never has more than 12,321 digits, plus a few digits for the carries.
+//
+// For parallel summation, each goroutine fills its own Accumulator, and the partial sums are combined with Merge.
+// An Accumulator must not be used by several goroutines at the same time.
+//
+// The zero value is an empty Accumulator, whose Result is 0.
+//
+type Accumulator struct {
+	coefficient big.Int // the exact sum of the finite operands is coefficient * 10^exp
+	exp         int32
+	hasPos      bool // a finite operand with positive sign has been added, used for the sign of a zero result
+	hasNeg      bool // a finite operand with negative sign has been added
+	posInf      bool // +Infinity has been added
+	negInf      bool // -Infinity has been added
+	nan         bool // NaN has been added
+	status      Status
+
+	scratch  big.Int // to avoid allocations in Add
+	scratch2 big.Int // also used by addScaled, as big.Int.Mul allocates if the result is one of its operands
+}
+
+// Add adds a to the sum.
+//
+func (acc *Accumulator) Add(a Quad) {
+
+	acc.add(a, false)
+}
+
+// Sub subtracts a from the sum.
+//
+func (acc *Accumulator) Sub(a Quad) {
+
+	acc.add(a, true)
+}
+
+// add adds a, or -a if negate is true, to the sum.
+//
+func (acc *Accumulator) add(a Quad, negate bool) {
+
+	hi, lo := a.words()
+
+	acc.status |= Status(a.status)
+	neg := (hi>>63 != 0) != negate
+
+	switch {
+	case hi&dpdNaNMask == dpdNaNMask:
+		acc.nan = true
+		if hi&(0x7e<<56) == 0x7e<<56 { // sNaN, as DFISSNAN() in decNumberLocal.h
+			acc.status |= InvalidOperation
+		}
+		return
+
+	case hi&dpdSpecialMask == dpdSpecialMask: // Infinity
+		if neg {
+			acc.negInf = true
+		} else {
+			acc.posInf = true
+		}
+		return
+	}
+
+	h, l := to128(dpdCoefficient(hi, lo))
+
+	c := acc.scratch.SetUint64(l)
+	if h != 0 {
+		c.Or(c, acc.scratch2.Lsh(acc.scratch2.SetUint64(h), 64))
+	}
+
+	if neg {
+		c.Neg(c)
+	}
+
+	acc.addScaled(c, dpdExponent(hi))
+
+	if neg {
+		acc.hasNeg = true
+	} else {
+		acc.hasPos = true
+	}
+}
+
+// addScaled adds c * 10^exp to the sum. c is not modified, but it can't be acc.scratch2.
+// The caller must then set hasPos or hasNeg.
+//
+// The coefficients are aligned exactly, without folding the low-order digits into a sticky digit as fromBCD does,
+// because a later operand can cancel the high-order digits. See the size of the coefficient in the description of Accumulator.
+//
+func (acc *Accumulator) addScaled(c *big.Int, exp int32) {
+
+	switch {
+	case !acc.hasPos && !acc.hasNeg: // first finite operand
+		acc.coefficient.Set(c)
+		acc.exp = exp
+
+	case exp > acc.exp:
+		acc.scratch2.Mul(c, tenPow(exp-acc.exp))
+		acc.coefficient.Add(&acc.coefficient, &acc.scratch2)
+
+	case exp < acc.exp: // the sum takes the smallest exponent, as for an exact Add
+		acc.scratch2.Mul(&acc.coefficient, tenPow(acc.exp-exp))
+		acc.coefficient.Add(&acc.scratch2, c)
+		acc.exp = exp
+
+	default:
+		acc.coefficient.Add(&acc.coefficient, c)
+	}
+}
+
+// Merge adds the sum of b to the sum of acc. b is not modified.
+// It is used to combine the partial sums computed in parallel.
+//
+func (acc *Accumulator) Merge(b *Accumulator) {
+
+	acc.status |= b.status
+	acc.nan = acc.nan || b.nan
+	acc.posInf = acc.posInf || b.posInf
+	acc.negInf = acc.negInf || b.negInf
+
+	if b.hasPos || b.hasNeg {
+		hasPos, hasNeg := b.hasPos, b.hasNeg // b can be acc
+
+		acc.addScaled(acc.scratch.Set(&b.coefficient), b.exp)
+
+		acc.hasPos = acc.hasPos || hasPos
+		acc.hasNeg = acc.hasNeg || hasNeg
+	}
+}
+
+// Reset empties the accumulator, so that it can be reused.
+//
+func (acc *Accumulator) Reset() {
+
+	acc.coefficient.SetUint64(0)
+	acc.exp = 0
+	acc.hasPos, acc.hasNeg, acc.posInf, acc.negInf, acc.nan = false, false, false, false, false
+	acc.status = 0
+}
+
+// Result returns the sum, rounded to 34 digits with the rounding mode passed as argument.
+// The accumulator is not modified, so that more numbers can be added after.
+//
+// The status of the result contains the status of all the operands, and the flags of the rounding, e.g. Inexact or Overflow.
+//
+// If the sum is exactly zero, its exponent is the smallest exponent of the operands, and its sign is negative only if all the operands are negative,
+// or if rounding is RoundFloor and an operand is negative, as for Add.
+//
+// If NaN has been added, or both +Infinity and -Infinity, the result is NaN, and InvalidOperation is set for sNaN or Infinity - Infinity.
+// The payload of NaN is not kept.
+//
+func (acc *Accumulator) Result(rounding RoundingMode) Quad {
+	var buff [bcdMax]byte
+
+	switch {
+	case acc.nan:
+		return NaN().SetStatusFlags(acc.status)
+	case acc.posInf && acc.negInf:
+		return NaN().SetStatusFlags(acc.status | InvalidOperation)
+	case acc.posInf:
+		return g_infinity.SetStatusFlags(acc.status)
+	case acc.negInf:
+		return g_infinity.Neg().SetStatusFlags(acc.status)
+	case !acc.hasPos && !acc.hasNeg:
+		return Zero().SetStatusFlags(acc.status)
+	}
+
+	neg := acc.coefficient.Sign() < 0
+
+	if acc.coefficient.Sign() == 0 {
+		neg = !acc.hasPos || rounding == RoundFloor && acc.hasNeg
+	}
+
+	return fromBCD(bigToBCD(buff[:0], &acc.coefficient), acc.exp, neg, rounding).SetStatusFlags(acc.status)
+}
@@ -0,0 +1,196 @@
+package decnum
+
+import (
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestAccumulator(t *testing.T) {
+
+	samples := []struct {
+		add      string
+		sub      string
+		rounding RoundingMode
+		expected string
+		status   Status
+	}{
+		{"", "", RoundHalfEven, "0", 0},
+		{"0.1 0.2 0.3", "", RoundHalfEven, "0.6", 0},
+		{"1.00 2.5", "", RoundHalfEven, "3.50", 0},
+		{"5", "3.5", RoundHalfEven, "1.5", 0},
+		{"1E+40 1 -1E+40", "", RoundHalfEven, "1", 0}, // Add gives 0
+		{"1E+40 1", "1E+40", RoundHalfEven, "1", 0},
+		{"1 1E-40", "", RoundHalfEven, "1.000000000000000000000000000000000", Inexact},
+		{"1 1E-40", "", RoundUp, "1.000000000000000000000000000000001", Inexact},
+		{"-1 -1E-40", "", RoundFloor, "-1.000000000000000000000000000000001", Inexact},
+		{"1.50 -1.5", "", RoundHalfEven, "0.00", 0},
+		{"1E-6176 1E-6176", "", RoundHalfEven, "2E-6176", 0},
+		{"1E+6144 1E-6176", "1E+6144", RoundHalfEven, "1E-6176", 0}, // largest spread, the coefficient has 12,321 digits
+		{"9.999999999999999999999999999999999E+6144 9E+6144", "", RoundHalfEven, "Infinity", Overflow | Inexact},
+		{"9.999999999999999999999999999999999E+6144 9E+6144", "", RoundDown, "9.999999999999999999999999999999999E+6144", Overflow | Inexact},
+		{"1 Infinity 1E+6144", "", RoundHalfEven, "Infinity", 0},
+		{"1 -Infinity", "", RoundHalfEven, "-Infinity", 0},
+		{"Infinity", "Infinity", RoundHalfEven, "NaN", InvalidOperation},
+		{"1 NaN Infinity", "", RoundHalfEven, "NaN", 0},
+		{"1", "sNaN", RoundHalfEven, "NaN", InvalidOperation},
+	}
+
+	for _, s := range samples {
+		var acc Accumulator
+
+		for _, f := range strings.Fields(s.add) {
+			acc.Add(must_quad(f))
+		}
+		for _, f := range strings.Fields(s.sub) {
+			acc.Sub(must_quad(f))
+		}
+
+		r := acc.Result(s.rounding)
+
+		if r.QuadToString() != s.expected || r.Status() != s.status {
+			t.Fatalf("sum of [%s] - [%s] with %s = %s %s, expected %s %s", s.add, s.sub, s.rounding, r.QuadToString(), r.Status(), s.expected, s.status)
+		}
+	}
+}
+
+func TestAccumulatorAllocations(t *testing.T) {
+	var acc Accumulator
+
+	inputs := []Quad{must_quad("1.5"), must_quad("-2.25"), must_quad("1E+3"), must_quad("0.125"), must_quad("-7E+20")}
+
+	acc.Add(must_quad("1E-10")) // the sum keeps exponent -10, so that the coefficients are aligned at each Add below
+
+	n := testing.AllocsPerRun(100, func() {
+		for _, a := range inputs {
+			acc.Add(a)
+		}
+	})
+
+	if n != 0 {
+		t.Fatalf("Accumulator.Add allocates %v times, expected 0", n)
+	}
+}
+
+func TestAccumulatorZeroSign(t *testing.T) {
+
+	samples := []struct {
+		add      string
+		rounding RoundingMode
+		negative bool
+	}{
+		{"-0 -0.00", RoundHalfEven, true},
+		{"-0 0", RoundHalfEven, false},
+		{"-0 0", RoundFloor, true},
+		{"1 -1", RoundHalfEven, false},
+		{"1 -1", RoundFloor, true},
+		{"0 0", RoundFloor, false},
+	}
+
+	for _, s := range samples {
+		var acc Accumulator
+
+		for _, f := range strings.Fields(s.add) {
+			acc.Add(must_quad(f))
+		}
+
+		r := acc.Result(s.rounding)
+
+		if hi, _ := r.words(); !r.IsZero() || (hi>>63 != 0) != s.negative {
+			t.Fatalf("sum of [%s] with %s = %x, expected a zero with negative sign %t", s.add, s.rounding, r.Bytes(), s.negative)
+		}
+	}
+}
+
+func TestAccumulatorRandom(t *testing.T) {
+
+	r := rand.New(rand.NewSource(50))
+	roundings := []RoundingMode{RoundCeiling, RoundDown, RoundFloor, RoundHalfDown, RoundHalfEven, RoundHalfUp, RoundUp, Round05Up}
+
+	for i := 0; i < 300; i++ {
+		a := make([]Quad, r.Intn(50)+1)
+		for j := range a {
+			a[j] = New(r.Int63()>>uint(r.Intn(63)), int32(r.Intn(80)-40))
+			if r.Intn(2) == 0 {
+				a[j] = a[j].Neg()
+			}
+		}
+
+		rounding := roundings[r.Intn(len(roundings))]
+
+		// exact sum
+
+		exact := new(big.Rat)
+		for _, x := range a {
+			q, err := x.ToRat()
+			if err != nil {
+				t.Fatalf("ToRat(%s) failed: %v", x, err)
+			}
+			exact.Add(exact, q)
+		}
+
+		var acc Accumulator
+		for _, x := range a {
+			acc.Add(x)
+		}
+
+		result := acc.Result(rounding)
+
+		if expected := FromRat(exact, rounding); !result.Equal(expected) || result.Status() != expected.Status() {
+			t.Fatalf("sum of %v with %s = %s %s, expected %s %s", a, rounding, result, result.Status(), expected, expected.Status())
+		}
+
+		// the result doesn't depend on the order, nor on the partition of the operands
+
+		r.Shuffle(len(a), func(i, j int) { a[i], a[j] = a[j], a[i] })
+
+		var parts [3]Accumulator
+		for _, x := range a {
+			parts[r.Intn(len(parts))].Add(x)
+		}
+
+		var merged Accumulator
+		for j := range parts {
+			merged.Merge(&parts[j])
+		}
+
+		if m := merged.Result(rounding); m.Bytes() != result.Bytes() || m.Status() != result.Status() {
+			t.Fatalf("merged sum of %v with %s = %s, expected %s", a, rounding, m.QuadToString(), result.QuadToString())
+		}
+
+		// without rounding, the result is the same as Sum, with the same exponent
+
+		if result.Status() == 0 {
+			if s := Sum(a); s.Bytes() != result.Bytes() {
+				t.Fatalf("sum of %v = %s, Sum gives %s", a, result.QuadToString(), s.QuadToString())
+			}
+		}
+
+		merged.Merge(&merged)
+		merged.Sub(result)
+		merged.Sub(result)
+
+		if m := merged.Result(rounding); !m.IsZero() && result.Status() == 0 {
+			t.Fatalf("sum of %v merged with itself, minus twice the sum, = %s, expected 0", a, m)
+		}
+
+		merged.Reset()
+		if m := merged.Result(rounding); m.Bytes() != Zero().Bytes() || m.Status() != 0 {
+			t.Fatalf("Result after Reset = %s, expected 0", m)
+		}
+	}
+}
+
+func BenchmarkAccumulator(b *testing.B) {
+
+	a := benchmarkAmounts()
+
+	for i := 0; i < b.N; i++ {
+		var acc Accumulator
+		for _, x := range a {
+			acc.Add(x)
+		}
+		acc.Result(RoundHalfEven)
+	}
+}
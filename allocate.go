@@ -0,0 +1,131 @@
+package decnum
+
+import (
+	"sort"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                       proportional allocation                        */
+/*                                                                      */
+/************************************************************************/
+
+// AllocationMode tells how Allocate distributes the units left over after rounding the parts down.
+//
+type AllocationMode int
+
+const (
+	AllocLargestRemainder AllocationMode = iota // one unit to each part with the largest remainder. Ties go to the first part. This is the Hamilton method.
+	AllocRoundRobin                             // one unit to each part with a non-zero ratio, in order, starting from the first part.
+)
+
+func (mode AllocationMode) String() string {
+
+	switch mode {
+	case AllocLargestRemainder:
+		return "AllocLargestRemainder"
+	case AllocRoundRobin:
+		return "AllocRoundRobin"
+	default:
+		return "Unknown allocation mode"
+	}
+}
+
+// Allocate splits total into parts proportional to ratios, each part having scale fractional digits, e.g. 2 for cents.
+// The parts always sum exactly to total.
+//
+// Each part is first rounded towards zero to the scale. Then, the units left over (e.g. the remaining cents) are given one by one to parts, as chosen by mode.
+// A part with a zero ratio is always 0.
+//
+//        Allocate(100.00, [1 1 1], 2, AllocLargestRemainder)    returns [33.34 33.33 33.33]
+//        Allocate(0.05, [1 3], 2, AllocLargestRemainder)        returns [0.01 0.04]          exact shares are 0.0125 and 0.0375
+//        Allocate(0.05, [1 3], 2, AllocRoundRobin)              returns [0.02 0.03]
+//
+// total must be finite and representable exactly with scale fractional digits, e.g. 100.005 cannot be allocated with scale 2: round it beforehand with RoundWithMode.
+// ratios must be finite, not negative, and their sum must not be 0.
+// Else, all the parts are NaN with InvalidOperation status.
+//
+// The status of total is copied to the parts.
+//
+func Allocate(total Quad, ratios []Quad, scale int32, mode AllocationMode) []Quad {
+	var (
+		parts      = make([]Quad, len(ratios))
+		remainders = make([]Quad, len(ratios))
+		sum        = Zero()
+	)
+
+	if len(ratios) == 0 {
+		return parts
+	}
+
+	invalid := func() []Quad {
+		for i := range parts {
+			parts[i] = NaN().SetStatusFlags(InvalidOperation)
+		}
+		return parts
+	}
+
+	unit := unitQuad(clampExp(-int64(scale)))
+
+	if !total.IsFinite() || !total.Quantize(unit, RoundDown).Equal(total) || mode < AllocLargestRemainder || mode > AllocRoundRobin {
+		return invalid()
+	}
+
+	for _, r := range ratios {
+		if !r.IsFinite() || r.IsNegative() {
+			return invalid()
+		}
+		sum = sum.Add(r)
+	}
+
+	if sum.IsZero() || sum.Error() != nil {
+		return invalid()
+	}
+
+	status := total.Status()
+	total = total.Quantize(unit, RoundDown).ClearStatus()
+
+	// parts rounded towards zero, and total of the left over units
+
+	leftover := total
+	for i, r := range ratios {
+		share := total.Mul(r).Div(sum) // rounded to 34 digits, but the rounding error is absorbed by leftover
+		parts[i] = share.Quantize(unit, RoundDown).ClearStatus()
+		remainders[i] = share.Sub(parts[i]).Abs()
+		leftover = leftover.Sub(parts[i])
+	}
+
+	n, _ := leftover.Div(unit).ToInt64(RoundHalfEven) // exact integer
+
+	step := unit
+	if n < 0 {
+		step = unit.Neg()
+		n = -n
+	}
+
+	// order of the parts receiving the left over units
+
+	order := make([]int, 0, len(ratios))
+	for i, r := range ratios {
+		if !r.IsZero() {
+			order = append(order, i)
+		}
+	}
+
+	if mode == AllocLargestRemainder {
+		sort.SliceStable(order, func(i, j int) bool {
+			return remainders[order[i]].Greater(remainders[order[j]])
+		})
+	}
+
+	for k := int64(0); k < n; k++ {
+		i := order[k%int64(len(order))]
+		parts[i] = parts[i].Add(step)
+	}
+
+	for i := range parts {
+		parts[i] = parts[i].ClearStatus().SetStatusFlags(status)
+	}
+
+	return parts
+}
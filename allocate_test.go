@@ -0,0 +1,100 @@
+package decnum
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAllocate(t *testing.T) {
+
+	samples := []struct {
+		total    string
+		ratios   string
+		scale    int32
+		mode     AllocationMode
+		expected string
+	}{
+		{"100.00", "1 1 1", 2, AllocLargestRemainder, "33.34 33.33 33.33"},
+		{"100", "1 1 1", 2, AllocLargestRemainder, "33.34 33.33 33.33"},
+		{"100.00", "1 1 1", 2, AllocRoundRobin, "33.34 33.33 33.33"},
+		{"0.05", "1 3", 2, AllocLargestRemainder, "0.01 0.04"},
+		{"0.05", "1 3", 2, AllocRoundRobin, "0.02 0.03"},
+		{"0.05", "3 7", 2, AllocLargestRemainder, "0.02 0.03"},
+		{"10.00", "0.2 0.3 0.5", 2, AllocLargestRemainder, "2.00 3.00 5.00"},
+		{"1.00", "1 1 1 1 1 1", 2, AllocLargestRemainder, "0.17 0.17 0.17 0.17 0.16 0.16"},
+		{"1.00", "1 2 3", 2, AllocLargestRemainder, "0.17 0.33 0.50"},
+		{"1.00", "1 2 3", 2, AllocRoundRobin, "0.17 0.33 0.50"},
+		{"0.10", "1 1 1 1", 2, AllocRoundRobin, "0.03 0.03 0.02 0.02"},
+		{"-100.00", "1 1 1", 2, AllocLargestRemainder, "-33.34 -33.33 -33.33"},
+		{"100", "1 0 1", 0, AllocLargestRemainder, "50 0 50"},
+		{"101", "1 0 1", 0, AllocRoundRobin, "51 0 50"},
+		{"7", "1 1 1", 0, AllocLargestRemainder, "3 2 2"},
+		{"1000", "1 1 1", -1, AllocLargestRemainder, "3.4E+2 3.3E+2 3.3E+2"},
+		{"0.00", "1 2", 2, AllocLargestRemainder, "0.00 0.00"},
+		{"0.01", "1 1 1", 2, AllocLargestRemainder, "0.01 0.00 0.00"},
+		{"123.45", "1", 2, AllocLargestRemainder, "123.45"},
+		{"99999999999999999999999999999999.99", "1 1 1", 2, AllocLargestRemainder, "33333333333333333333333333333333.33 33333333333333333333333333333333.33 33333333333333333333333333333333.33"},
+	}
+
+	for _, s := range samples {
+		var ratios []Quad
+		for _, r := range strings.Fields(s.ratios) {
+			ratios = append(ratios, must_quad(r))
+		}
+
+		parts := Allocate(must_quad(s.total), ratios, s.scale, s.mode)
+
+		strs := make([]string, len(parts))
+		sum := Zero()
+		for i, p := range parts {
+			strs[i] = p.String()
+			sum = sum.Add(p)
+			if p.Error() != nil {
+				t.Fatalf("Allocate(%s, [%s], %d, %s) part %d has error %v", s.total, s.ratios, s.scale, s.mode, i, p.Error())
+			}
+		}
+
+		if r := strings.Join(strs, " "); r != s.expected {
+			t.Fatalf("Allocate(%s, [%s], %d, %s) = [%s], expected [%s]", s.total, s.ratios, s.scale, s.mode, r, s.expected)
+		}
+
+		if !sum.Equal(must_quad(s.total)) {
+			t.Fatalf("Allocate(%s, [%s], %d, %s) parts sum to %s", s.total, s.ratios, s.scale, s.mode, sum)
+		}
+	}
+
+	if parts := Allocate(must_quad("1"), nil, 2, AllocLargestRemainder); len(parts) != 0 {
+		t.Fatalf("Allocate with no ratio must return no part, got %v", parts)
+	}
+}
+
+func TestAllocateInvalid(t *testing.T) {
+
+	samples := []struct {
+		total  string
+		ratios []string
+		scale  int32
+		mode   AllocationMode
+	}{
+		{"100.005", []string{"1", "1"}, 2, AllocLargestRemainder},
+		{"NaN", []string{"1", "1"}, 2, AllocLargestRemainder},
+		{"Inf", []string{"1", "1"}, 2, AllocLargestRemainder},
+		{"100", []string{"1", "-1"}, 2, AllocLargestRemainder},
+		{"100", []string{"0", "0"}, 2, AllocLargestRemainder},
+		{"100", []string{"1", "NaN"}, 2, AllocLargestRemainder},
+		{"100", []string{"1", "1"}, 2, AllocationMode(9)},
+	}
+
+	for _, s := range samples {
+		var ratios []Quad
+		for _, r := range s.ratios {
+			ratios = append(ratios, must_quad(r))
+		}
+
+		for _, p := range Allocate(must_quad(s.total), ratios, s.scale, s.mode) {
+			if !p.IsNaN() || p.Status()&InvalidOperation == 0 {
+				t.Fatalf("Allocate(%s, %v, %d, %s) = %s, expected NaN with InvalidOperation", s.total, s.ratios, s.scale, s.mode, p)
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package decnum
+
+import (
+	"encoding/binary"
+	"math/big"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*           Arrow / Parquet fixed-width decimal conversion             */
+/*                                                                      */
+/************************************************************************/
+
+// Byte widths and max precisions of the Arrow and Parquet fixed-width decimal types.
+//
+// A DECIMAL(precision, scale) value is stored as a two's complement unscaled integer, and its value is unscaled * 10^-scale.
+// Arrow stores it in little-endian byte order, Parquet FIXED_LEN_BYTE_ARRAY in big-endian byte order.
+//
+const (
+	Decimal128Width        = 16 // size in bytes of an Arrow decimal128 value
+	Decimal256Width        = 32 // size in bytes of an Arrow decimal256 value
+	Decimal128MaxPrecision = 38 // max precision of an Arrow decimal128 value
+	Decimal256MaxPrecision = 76 // max precision of an Arrow decimal256 value
+)
+
+// AppendDecimal128 appends src to dst as DECIMAL(precision, scale) values, 16 bytes per element, and returns the extended buffer.
+//
+// Values with more fractional digits than scale are rounded with the rounding mode passed as argument.
+// The status returned for each element is its own status, plus the flags set by the conversion:
+//
+//        Inexact             if rounding occurred
+//        Overflow            if the rounded value has more than precision digits. Zero is written.
+//        InvalidOperation    if the value is Inf or NaN, or precision is not in [1..38]. Zero is written.
+//
+// Pass binary.LittleEndian for Arrow, and binary.BigEndian for Parquet.
+//
+func AppendDecimal128(dst []byte, src []Quad, precision int32, scale int32, rounding RoundingMode, order binary.ByteOrder) ([]byte, []Status) {
+
+	return appendDecimal(dst, src, Decimal128Width, Decimal128MaxPrecision, precision, scale, rounding, order)
+}
+
+// AppendDecimal256 is the same as AppendDecimal128, but writes 32 bytes per element, and precision must be in [1..76].
+//
+func AppendDecimal256(dst []byte, src []Quad, precision int32, scale int32, rounding RoundingMode, order binary.ByteOrder) ([]byte, []Status) {
+
+	return appendDecimal(dst, src, Decimal256Width, Decimal256MaxPrecision, precision, scale, rounding, order)
+}
+
+// DecodeDecimal128 converts src, which contains DECIMAL(precision, scale) values of 16 bytes each, to Quads.
+//
+// If len(src) is not a multiple of 16, InvalidOperation is returned as error, and no value is converted.
+//
+// Each Quad contains its own status:
+//
+//        Inexact             if the unscaled value has more than 34 digits, and has been rounded with the rounding mode passed as argument
+//        Overflow            if the unscaled value has more than precision digits. The value is still converted.
+//
+func DecodeDecimal128(src []byte, precision int32, scale int32, rounding RoundingMode, order binary.ByteOrder) ([]Quad, error) {
+
+	return decodeDecimal(src, Decimal128Width, Decimal128MaxPrecision, precision, scale, rounding, order)
+}
+
+// DecodeDecimal256 is the same as DecodeDecimal128, but reads 32 bytes per element, and precision must be in [1..76].
+//
+func DecodeDecimal256(src []byte, precision int32, scale int32, rounding RoundingMode, order binary.ByteOrder) ([]Quad, error) {
+
+	return decodeDecimal(src, Decimal256Width, Decimal256MaxPrecision, precision, scale, rounding, order)
+}
+
+func appendDecimal(dst []byte, src []Quad, width int, maxPrecision int32, precision int32, scale int32, rounding RoundingMode, order binary.ByteOrder) ([]byte, []Status) {
+	var (
+		status   = make([]Status, len(src))
+		unscaled big.Int
+		bigEnd   = isBigEndian(order)
+	)
+
+	for i, a := range src {
+		start := len(dst)
+		for j := 0; j < width; j++ {
+			dst = append(dst, 0)
+		}
+
+		if precision < 1 || precision > maxPrecision {
+			status[i] = a.Status() | InvalidOperation
+			continue
+		}
+
+		status[i] = a.toUnscaled(&unscaled, precision, scale, rounding)
+		if status[i]&ErrorMask != 0 {
+			continue
+		}
+
+		out := dst[start:]
+		putTwosComplement(out, &unscaled) // always fits, as precision <= maxPrecision
+
+		if !bigEnd {
+			reverseBytes(out)
+		}
+	}
+
+	return dst, status
+}
+
+func decodeDecimal(src []byte, width int, maxPrecision int32, precision int32, scale int32, rounding RoundingMode, order binary.ByteOrder) ([]Quad, error) {
+	var (
+		buff     [Decimal256Width]byte
+		limit    *big.Int // 10^precision
+		unscaled big.Int
+		bigEnd   = isBigEndian(order)
+	)
+
+	if len(src)%width != 0 || precision < 1 || precision > maxPrecision {
+		return nil, QuadError(InvalidOperation)
+	}
+
+	limit = tenPow(precision)
+
+	result := make([]Quad, 0, len(src)/width)
+
+	for start := 0; start < len(src); start += width {
+		in := buff[:width]
+		copy(in, src[start:start+width])
+
+		if !bigEnd {
+			reverseBytes(in)
+		}
+
+		twosComplementToBig(&unscaled, in)
+		q := fromUnscaled(&unscaled, scale, rounding)
+
+		if unscaled.CmpAbs(limit) >= 0 {
+			q = q.SetStatusFlags(Overflow)
+		}
+
+		result = append(result, q)
+	}
+
+	return result, nil
+}
+
+// isBigEndian returns true if order writes the most significant byte first.
+//
+func isBigEndian(order binary.ByteOrder) bool {
+	var b [2]byte
+
+	order.PutUint16(b[:], 1)
+
+	return b[0] == 0
+}
+
+// reverseBytes reverses the order of the bytes of b, to convert between big-endian and little-endian.
+//
+func reverseBytes(b []byte) {
+
+	for l, r := 0, len(b)-1; l < r; l, r = l+1, r-1 {
+		b[l], b[r] = b[r], b[l]
+	}
+}
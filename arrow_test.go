@@ -0,0 +1,121 @@
+package decnum
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+)
+
+func Test_decimal128(t *testing.T) {
+
+	var samples = []struct {
+		a               string
+		precision       int32
+		scale           int32
+		rounding        RoundingMode
+		expected_hex    string // big-endian
+		expected_status Status
+		expected_back   string
+	}{
+		{"0", 10, 2, RoundHalfEven, "00000000000000000000000000000000", 0, "0.00"},
+		{"1", 10, 2, RoundHalfEven, "00000000000000000000000000000064", 0, "1.00"},
+		{"-1", 10, 2, RoundHalfEven, "ffffffffffffffffffffffffffffff9c", 0, "-1.00"},
+		{"123.456", 10, 2, RoundHalfEven, "0000000000000000000000000000303a", Inexact, "123.46"},
+		{"123.455", 10, 2, RoundDown, "00000000000000000000000000003039", Inexact, "123.45"},
+		{"-123.455", 10, 2, RoundFloor, "ffffffffffffffffffffffffffffcfc6", Inexact, "-123.46"},
+		{"1e3", 10, 2, RoundHalfEven, "000000000000000000000000000186a0", 0, "1000.00"},
+		{"12", 10, -1, RoundHalfEven, "00000000000000000000000000000001", Inexact, "1E+1"},
+		{"99999999.99", 10, 2, RoundHalfEven, "000000000000000000000002540be3ff", 0, "99999999.99"},
+		{"99999999.995", 10, 2, RoundHalfEven, "00000000000000000000000000000000", Inexact | Overflow, ""},
+		{"100000000", 10, 2, RoundHalfEven, "00000000000000000000000000000000", Overflow, ""},
+		{"0.5", 38, 38, RoundHalfEven, "259da6542d43623d04c5112000000000", 0, "0.5000000000000000000000000000000000"}, // 38 digits, rounded to 34 digits exactly,
+		{"Inf", 10, 2, RoundHalfEven, "00000000000000000000000000000000", InvalidOperation, ""},
+		{"NaN", 10, 2, RoundHalfEven, "00000000000000000000000000000000", InvalidOperation, ""},
+		{"1", 39, 2, RoundHalfEven, "00000000000000000000000000000000", InvalidOperation, ""},
+	}
+
+	for _, s := range samples {
+		a := must_quad(s.a)
+
+		be, st := AppendDecimal128(nil, []Quad{a}, s.precision, s.scale, s.rounding, binary.BigEndian)
+		if hex.EncodeToString(be) != s.expected_hex || st[0] != s.expected_status {
+			t.Fatalf("AppendDecimal128(%s, %d, %d, %s) = %x %s, expected %s %s", s.a, s.precision, s.scale, s.rounding, be, st[0], s.expected_hex, s.expected_status)
+		}
+
+		le, _ := AppendDecimal128(nil, []Quad{a}, s.precision, s.scale, s.rounding, binary.LittleEndian)
+		for i := range le {
+			if le[i] != be[len(be)-1-i] {
+				t.Fatalf("AppendDecimal128(%s) little-endian %x is not reverse of big-endian %x", s.a, le, be)
+			}
+		}
+
+		if s.expected_back == "" {
+			continue
+		}
+
+		back, err := DecodeDecimal128(le, s.precision, s.scale, RoundHalfEven, binary.LittleEndian)
+		if err != nil || len(back) != 1 || back[0].String() != s.expected_back || back[0].Error() != nil {
+			t.Fatalf("DecodeDecimal128(%x) = %v %v, expected %s", le, back, err, s.expected_back)
+		}
+	}
+}
+
+func Test_decimal256(t *testing.T) {
+
+	src := []Quad{must_quad("1234567890123456789012345678901234"), must_quad("-0.000001"), must_quad("1E+40")}
+
+	buf, st := AppendDecimal256([]byte{0xAA}, src, 76, 30, RoundHalfEven, binary.LittleEndian)
+	if len(buf) != 1+3*Decimal256Width || buf[0] != 0xAA {
+		t.Fatalf("AppendDecimal256 returned buffer of length %d", len(buf))
+	}
+	for i, s := range st {
+		if s != 0 {
+			t.Fatalf("AppendDecimal256 element %d has status %s", i, s)
+		}
+	}
+
+	back, err := DecodeDecimal256(buf[1:], 76, 30, RoundHalfEven, binary.LittleEndian)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// values come back with exponent -30, unless the unscaled value has more than 34 digits
+
+	expected := []struct {
+		s      string
+		status Status
+	}{
+		{"1234567890123456789012345678901234", 0},
+		{"-0.000001000000000000000000000000", 0},
+		{"1.000000000000000000000000000000000E+40", 0},
+	}
+
+	for i, e := range expected {
+		if back[i].String() != e.s || back[i].Status() != e.status || !back[i].Equal(src[i]) {
+			t.Fatalf("DecodeDecimal256 element %d is %s %s, expected %s %s", i, back[i], back[i].Status(), e.s, e.status)
+		}
+	}
+
+	// unscaled value rounded on decoding
+
+	in := bytes.Repeat([]byte{0xFF}, Decimal256Width) // big-endian 2^200-1 - (2^256-2^200), 60 digits
+	for i := 0; i < 7; i++ {
+		in[i] = 0
+	}
+	in[7] = 0x80
+
+	back, _ = DecodeDecimal256(in, 76, 0, RoundDown, binary.BigEndian)
+	if back[0].String() != "8.097461238648818185348168355937889E+59" || back[0].Status() != Inexact {
+		t.Fatalf("DecodeDecimal256 returned %s %s", back[0], back[0].Status())
+	}
+
+	if _, err = DecodeDecimal128(make([]byte, 17), 10, 2, RoundHalfEven, binary.LittleEndian); err == nil {
+		t.Fatal("DecodeDecimal128 should fail on a truncated buffer")
+	}
+
+	back, _ = DecodeDecimal128(make([]byte, 16), 10, 2, RoundHalfEven, binary.LittleEndian)
+	if back[0].String() != "0.00" {
+		t.Fatalf("DecodeDecimal128 returned %s", back[0])
+	}
+}
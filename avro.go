@@ -0,0 +1,99 @@
+package decnum
+
+import (
+	"math/big"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                        Avro decimal logical type                     */
+/*                                                                      */
+/************************************************************************/
+
+// AppendAvroBytes appends a to dst, encoded as the value of an Avro "decimal" logical type annotating "bytes".
+// The value is the unscaled integer a * 10^scale, as a big-endian two's complement integer with the minimal number of bytes.
+// Only the value is written. The length prefix is written by the Avro encoder.
+//
+// If a has more than scale fractional digits, it is rounded with the rounding mode passed as argument.
+//
+// An error is returned if a is Inf or NaN, or if the schema is invalid, that is, precision < 1, scale < 0 or scale > precision (InvalidOperation),
+// or if the unscaled integer has more than precision digits (Overflow).
+// In this case, dst is returned unchanged.
+//
+func (a Quad) AppendAvroBytes(dst []byte, precision int32, scale int32, rounding RoundingMode) ([]byte, error) {
+	var unscaled big.Int
+
+	if !avroValidSchema(precision, scale) {
+		return dst, newError(a.Status() | InvalidOperation)
+	}
+
+	if status := a.toUnscaled(&unscaled, precision, scale, rounding); status&ErrorMask != 0 {
+		return dst, newError(status)
+	}
+
+	start := len(dst)
+	for n := twosComplementLen(&unscaled); n > 0; n-- {
+		dst = append(dst, 0)
+	}
+
+	putTwosComplement(dst[start:], &unscaled)
+
+	return dst, nil
+}
+
+// AppendAvroFixed appends a to dst, encoded as the value of an Avro "decimal" logical type annotating a "fixed" of size bytes.
+// The value is the unscaled integer a * 10^scale, as a big-endian two's complement integer, sign-extended to size bytes.
+//
+// If a has more than scale fractional digits, it is rounded with the rounding mode passed as argument.
+//
+// An error is returned if a is Inf or NaN, or if the schema is invalid, as for AppendAvroBytes (InvalidOperation),
+// or if the unscaled integer has more than precision digits or doesn't fit in size bytes (Overflow).
+// In this case, dst is returned unchanged.
+//
+func (a Quad) AppendAvroFixed(dst []byte, size int, precision int32, scale int32, rounding RoundingMode) ([]byte, error) {
+	var unscaled big.Int
+
+	if !avroValidSchema(precision, scale) {
+		return dst, newError(a.Status() | InvalidOperation)
+	}
+
+	if status := a.toUnscaled(&unscaled, precision, scale, rounding); status&ErrorMask != 0 {
+		return dst, newError(status)
+	}
+
+	if size < 1 || twosComplementLen(&unscaled) > size {
+		return dst, newError(Overflow)
+	}
+
+	start := len(dst)
+	for n := size; n > 0; n-- {
+		dst = append(dst, 0)
+	}
+
+	putTwosComplement(dst[start:], &unscaled)
+
+	return dst, nil
+}
+
+// FromAvroDecimal returns a Quad from the value of an Avro "decimal" logical type, annotating "bytes" or "fixed".
+// b is the big-endian two's complement unscaled integer, and scale is the scale of the Avro schema.
+//
+// If the unscaled integer has more than 34 digits, it is rounded with the rounding mode passed as argument, and Inexact is set.
+// An empty b is zero.
+//
+// This function returns result.Error() as a convenience.
+//
+func FromAvroDecimal(b []byte, scale int32, rounding RoundingMode) (result Quad, err error) {
+	var unscaled big.Int
+
+	result = fromUnscaled(twosComplementToBig(&unscaled, b), scale, rounding)
+
+	return result, result.Error()
+}
+
+// avroValidSchema returns true if precision and scale are valid for an Avro "decimal" logical type: 1 <= precision, and 0 <= scale <= precision.
+//
+func avroValidSchema(precision int32, scale int32) bool {
+
+	return precision >= 1 && scale >= 0 && scale <= precision
+}
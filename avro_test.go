@@ -0,0 +1,86 @@
+package decnum
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func Test_avro(t *testing.T) {
+
+	var samples = []struct {
+		a              string
+		precision      int32
+		scale          int32
+		expected_bytes string // hex
+		expected_fixed string // hex, 4 bytes
+		expected_error Status
+		expected_back  string
+	}{
+		{"0", 9, 2, "00", "00000000", 0, "0.00"},
+		{"1", 9, 2, "64", "00000064", 0, "1.00"},
+		{"-1", 9, 2, "9c", "ffffff9c", 0, "-1.00"},
+		{"1.28", 9, 2, "0080", "00000080", 0, "1.28"},
+		{"-1.28", 9, 2, "80", "ffffff80", 0, "-1.28"},
+		{"-1.29", 9, 2, "ff7f", "ffffff7f", 0, "-1.29"},
+		{"123.456", 9, 2, "3039", "00003039", 0, "123.45"}, // RoundDown
+		{"21474836.47", 10, 2, "7fffffff", "7fffffff", 0, "21474836.47"},
+		{"21474836.48", 10, 2, "0080000000", "", Overflow, "21474836.48"}, // doesn't fit in 4 bytes
+		{"1e9", 9, 0, "", "", Overflow, ""},
+		{"NaN", 9, 2, "", "", InvalidOperation, ""},
+	}
+
+	for _, s := range samples {
+		a := must_quad(s.a)
+
+		b, err := a.AppendAvroBytes(nil, s.precision, s.scale, RoundDown)
+		if hex.EncodeToString(b) != s.expected_bytes || (err == nil) != (s.expected_bytes != "") {
+			t.Fatalf("AppendAvroBytes(%s) = %x %v, expected %s", s.a, b, err, s.expected_bytes)
+		}
+
+		f, err := a.AppendAvroFixed(nil, 4, s.precision, s.scale, RoundDown)
+		if hex.EncodeToString(f) != s.expected_fixed {
+			t.Fatalf("AppendAvroFixed(%s) = %x, expected %s", s.a, f, s.expected_fixed)
+		}
+		if s.expected_fixed == "" && (err == nil || Status(err.(QuadError)) != s.expected_error) {
+			t.Fatalf("AppendAvroFixed(%s) returned error %v, expected %s", s.a, err, s.expected_error)
+		}
+
+		if s.expected_back == "" {
+			continue
+		}
+
+		for _, enc := range []string{s.expected_bytes, s.expected_fixed} {
+			if enc == "" {
+				continue
+			}
+			raw, _ := hex.DecodeString(enc)
+			back, err := FromAvroDecimal(raw, s.scale, RoundHalfEven)
+			if err != nil || back.String() != s.expected_back {
+				t.Fatalf("FromAvroDecimal(%s) = %s %v, expected %s", enc, back, err, s.expected_back)
+			}
+		}
+	}
+
+	// invalid schema
+
+	for _, ps := range [][2]int32{{0, 0}, {-1, 0}, {9, -1}, {2, 3}} {
+		if b, err := must_quad("1").AppendAvroBytes(nil, ps[0], ps[1], RoundDown); b != nil || err != QuadError(InvalidOperation) {
+			t.Fatalf("AppendAvroBytes(1, %d, %d) = %x %v, expected error", ps[0], ps[1], b, err)
+		}
+		if f, err := must_quad("1").AppendAvroFixed(nil, 4, ps[0], ps[1], RoundDown); f != nil || err != QuadError(InvalidOperation) {
+			t.Fatalf("AppendAvroFixed(1, %d, %d) = %x %v, expected error", ps[0], ps[1], f, err)
+		}
+	}
+
+	// more than 34 digits in the unscaled integer
+
+	raw, _ := hex.DecodeString("7fffffffffffffffffffffffffffffff") // 2^127-1, 39 digits
+	back, err := FromAvroDecimal(raw, 4, RoundDown)
+	if err != nil || back.String() != "1.701411834604692317316873037158841E+34" || back.Status() != Inexact {
+		t.Fatalf("FromAvroDecimal(%x) = %s %s", raw, back, back.Status())
+	}
+
+	if back, _ = FromAvroDecimal(nil, 2, RoundHalfEven); back.String() != "0.00" {
+		t.Fatalf("FromAvroDecimal(nil) = %s", back)
+	}
+}
@@ -0,0 +1,348 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+import (
+	"math/big"
+	"unsafe"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                 coefficient as BCD, internal helpers                 */
+/*                                                                      */
+/************************************************************************/
+
+const bcdMax = C.MDQ_BCD_MAX // max number of digits accepted by C.mdq_from_BCD()
+
+// toBCD returns the coefficient of a as DecquadPmax digits, one digit per byte, most significant digit first.
+//
+// neg is true if a is negative and not zero.
+// If a is Inf or NaN, infNaN is C.MDQ_INFINITE or C.MDQ_NAN, and exp is 0. neg is valid for Inf.
+//
+func (a Quad) toBCD() (bcd [DecquadPmax]byte, exp int32, neg bool, infNaN uint32) {
+	var ret C.Ret_BCD
+
+	ret = C.mdq_to_BCD(a.val)
+
+	for i := 0; i < DecquadPmax; i++ {
+		bcd[i] = byte(ret.BCD[i])
+	}
+
+	return bcd, int32(ret.exp), ret.sign != 0, uint32(ret.inf_nan)
+}
+
+// fromBCD returns the Quad (-1)^neg * bcd * 10^exp.
+//
+// bcd contains one digit per byte, most significant digit first. Leading zeros are allowed. An empty bcd is zero.
+// If bcd has more than DecquadPmax significant digits, the coefficient is rounded with the rounding mode, and Inexact is set.
+//
+func fromBCD(bcd []byte, exp int32, neg bool, rounding RoundingMode) Quad {
+	var (
+		sticky [bcdMax]byte
+		sign   C.uint32_t
+	)
+
+	for len(bcd) > 1 && bcd[0] == 0 { // strip leading zeros
+		bcd = bcd[1:]
+	}
+
+	if len(bcd) == 0 {
+		bcd = []byte{0}
+	}
+
+	if len(bcd) > bcdMax {
+		// Keep bcdMax-1 digits, and replace all the discarded digits by a single sticky digit, 1 if any of them is not 0.
+		// The rounding position (34th digit) is far away from the sticky digit, so the rounding result is the same for all rounding modes.
+
+		copy(sticky[:], bcd[:bcdMax-1])
+		for _, d := range bcd[bcdMax-1:] {
+			if d != 0 {
+				sticky[bcdMax-1] = 1
+				break
+			}
+		}
+
+		exp = clampExp(int64(exp) + int64(len(bcd)-bcdMax))
+		bcd = sticky[:]
+	}
+
+	if neg {
+		sign = 1
+	}
+
+	return Quad(C.mdq_from_BCD((*C.uint8_t)(unsafe.Pointer(&bcd[0])), C.int32_t(len(bcd)), C.int32_t(exp), sign, C.int(rounding)))
+}
+
+// unitQuad returns 1Eexp, e.g. 0.01 for exp -2. It is used as model for Quantize.
+//
+func unitQuad(exp int32) Quad {
+
+	return fromBCD([]byte{1}, exp, false, RoundHalfEven)
+}
+
+// clampExp converts an exponent to int32, saturating it if it is out of range.
+// Any value outside int32 range is anyway an Overflow or Underflow for a Quad.
+//
+func clampExp(exp int64) int32 {
+
+	if exp > 1<<30 {
+		return 1 << 30
+	}
+
+	if exp < -1<<30 {
+		return -1 << 30
+	}
+
+	return int32(exp)
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                  big.Int <-> BCD, internal helpers                   */
+/*                                                                      */
+/************************************************************************/
+
+const (
+	bigChunkDigits = 19                   // number of decimal digits in a chunk, so that a chunk fits in an uint64
+	bigChunk       = 10000000000000000000 // 10^bigChunkDigits
+)
+
+var bigChunkInt = new(big.Int).SetUint64(bigChunk)
+
+// bigToBCD appends the decimal digits of |x| to dst, one digit per byte, most significant digit first.
+// Zero is written as a single 0 digit.
+//
+// It doesn't use the string conversion of big.Int.
+//
+func bigToBCD(dst []byte, x *big.Int) []byte {
+	var (
+		q      big.Int
+		r      big.Int
+		chunks []uint64 // least significant chunk first
+	)
+
+	q.Abs(x)
+
+	for q.Sign() != 0 {
+		q.QuoRem(&q, bigChunkInt, &r)
+		chunks = append(chunks, r.Uint64())
+	}
+
+	if len(chunks) == 0 {
+		return append(dst, 0)
+	}
+
+	for i := len(chunks) - 1; i >= 0; i-- {
+		dst = appendChunk(dst, chunks[i], i != len(chunks)-1)
+	}
+
+	return dst
+}
+
+// appendChunk appends the digits of v to dst.
+// If full is true, exactly bigChunkDigits digits are written, with leading zeros.
+//
+func appendChunk(dst []byte, v uint64, full bool) []byte {
+	var (
+		buff [bigChunkDigits]byte
+		i    int = bigChunkDigits
+	)
+
+	for v != 0 || (full && i > 0) || i == bigChunkDigits {
+		i--
+		buff[i] = byte(v % 10)
+		v /= 10
+	}
+
+	return append(dst, buff[i:]...)
+}
+
+// bcdToBig sets z to the integer whose decimal digits are bcd, most significant digit first, and returns z.
+//
+func bcdToBig(z *big.Int, bcd []byte) *big.Int {
+	var (
+		chunk big.Int
+		v     uint64
+		n     int
+	)
+
+	z.SetUint64(0)
+
+	for _, d := range bcd {
+		v = v*10 + uint64(d)
+		n++
+
+		if n == bigChunkDigits {
+			z.Mul(z, bigChunkInt)
+			z.Add(z, chunk.SetUint64(v))
+			v, n = 0, 0
+		}
+	}
+
+	if n > 0 {
+		z.Mul(z, chunk.Exp(big.NewInt(10), big.NewInt(int64(n)), nil))
+		z.Add(z, chunk.SetUint64(v))
+	}
+
+	return z
+}
+
+/************************************************************************/
+/*                                                                      */
+/*             unscaled integer and two's complement helpers            */
+/*                                                                      */
+/************************************************************************/
+
+// tenPowCached is the number of powers of 10 cached by tenPow. It is larger than the precision of the Arrow decimal256 type,
+// and than the exponent spread of most sums in Accumulator.
+//
+const tenPowCached = 80
+
+// tenPows contains 10^n, for n < tenPowCached.
+//
+var tenPows = func() (t [tenPowCached]*big.Int) {
+	t[0] = big.NewInt(1)
+	for n := 1; n < tenPowCached; n++ {
+		t[n] = new(big.Int).Mul(t[n-1], big.NewInt(10))
+	}
+	return t
+}()
+
+// tenPow returns 10^n, n >= 0.
+// For n < tenPowCached, the result is shared and doesn't allocate memory: it must not be modified.
+//
+func tenPow(n int32) *big.Int {
+
+	if n < tenPowCached {
+		return tenPows[n]
+	}
+
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// toUnscaled sets z to the unscaled integer a * 10^scale.
+// If a has more than scale fractional digits, it is first rounded with the rounding mode passed as argument.
+//
+// It returns the status of a, plus the flags set by the conversion:
+//
+//        Inexact             if rounding occurred
+//        Overflow            if the unscaled integer has more than precision digits
+//        InvalidOperation    if a is Inf or NaN
+//
+// If an error flag is returned, z is set to 0.
+//
+func (a Quad) toUnscaled(z *big.Int, precision int32, scale int32, rounding RoundingMode) Status {
+	var digits [DecquadPmax + 80]byte
+
+	z.SetUint64(0)
+
+	if !a.IsFinite() {
+		return a.Status() | InvalidOperation
+	}
+
+	// round the fractional part if it has more than scale digits. The coefficient can only become shorter.
+
+	if int64(a.GetExponent()) < -int64(scale) {
+		a = a.Quantize(fromBCD([]byte{1}, clampExp(-int64(scale)), false, RoundHalfEven), rounding) // model 1E-scale
+		if a.ErrorStatus() != 0 {
+			return a.Status()
+		}
+	}
+
+	if a.IsZero() {
+		return a.Status()
+	}
+
+	bcd, exp, neg, _ := a.toBCD()
+
+	// unscaled = coefficient * 10^(exp+scale), with exp+scale >= 0
+
+	shift := int64(exp) + int64(scale)
+	if shift > int64(precision) { // coefficient is not zero, so it has surely more than precision digits
+		return a.Status() | Overflow
+	}
+
+	coef := append(digits[:0], bcd[:]...)
+	for ; shift > 0; shift-- {
+		coef = append(coef, 0)
+	}
+
+	bcdToBig(z, coef)
+
+	if z.Cmp(tenPow(precision)) >= 0 {
+		z.SetUint64(0)
+		return a.Status() | Overflow
+	}
+
+	if neg {
+		z.Neg(z)
+	}
+
+	return a.Status()
+}
+
+// fromUnscaled returns the Quad x * 10^-scale.
+// If x has more than DecquadPmax digits, it is rounded with the rounding mode passed as argument, and Inexact is set.
+//
+func fromUnscaled(x *big.Int, scale int32, rounding RoundingMode) Quad {
+	var digits [DecquadPmax + 80]byte
+
+	return fromBCD(bigToBCD(digits[:0], x), clampExp(-int64(scale)), x.Sign() < 0, rounding)
+}
+
+// putTwosComplement writes x into out, as a big-endian two's complement integer.
+// It returns false if x doesn't fit in len(out) bytes.
+//
+func putTwosComplement(out []byte, x *big.Int) bool {
+	var modulus big.Int
+
+	if twosComplementLen(x) > len(out) {
+		return false
+	}
+
+	if x.Sign() >= 0 {
+		x.FillBytes(out)
+		return true
+	}
+
+	modulus.Lsh(big.NewInt(1), uint(8*len(out)))
+	modulus.Add(&modulus, x) // 2^(8*len(out)) - |x|
+	modulus.FillBytes(out)
+
+	return true
+}
+
+// twosComplementLen returns the minimal number of bytes needed to store x as two's complement integer.
+//
+func twosComplementLen(x *big.Int) int {
+	var m big.Int
+
+	if x.Sign() >= 0 {
+		return x.BitLen()/8 + 1 // one more bit for the sign
+	}
+
+	m.Neg(x)
+	m.Sub(&m, big.NewInt(1)) // -x-1 has the same number of significant bits as x in two's complement
+
+	return m.BitLen()/8 + 1
+}
+
+// twosComplementToBig sets z to the big-endian two's complement integer contained in b, and returns z.
+//
+func twosComplementToBig(z *big.Int, b []byte) *big.Int {
+	var modulus big.Int
+
+	z.SetBytes(b)
+
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		modulus.Lsh(big.NewInt(1), uint(8*len(b)))
+		z.Sub(z, &modulus)
+	}
+
+	return z
+}
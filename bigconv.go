@@ -0,0 +1,226 @@
+package decnum
+
+import (
+	"math/big"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                 conversion to and from big.Int, big.Rat, big.Float   */
+/*                                                                      */
+/************************************************************************/
+
+const (
+	log10Of2 = 0.30102999566398120 // number of decimal digits per bit
+
+	bigMaxMag = 6144 + 60       // decimal magnitude above which a value surely overflows. Emax is 6144.
+	bigMinMag = -6143 - 34 - 60 // decimal magnitude below which a value surely underflows. Emin is -6143, and subnormals have 33 more digits.
+)
+
+// ToBigInt returns a rounded to an integer with the rounding mode passed as argument, as a big.Int.
+//
+// If a is not an integer, it is rounded, and the rounded value is returned with QuadError(Inexact) as error.
+// Use errors.Is(err, QuadError(Inexact)) to accept it.
+// The accuracy tells if the result is exactly a, or below or above a, as for big.Float.Int.
+// If a is NaN or Infinity, nil, big.Exact and QuadError(InvalidOperation) are returned.
+//
+// The status field of a is not checked.
+//
+func (a Quad) ToBigInt(rounding RoundingMode) (*big.Int, big.Accuracy, error) {
+
+	if !a.IsFinite() {
+		return nil, big.Exact, QuadError(InvalidOperation)
+	}
+
+	if exp := a.GetExponent(); exp >= 0 {
+		z := a.Coefficient()
+		return z.Mul(z, tenPow(exp)), big.Exact, nil
+	}
+
+	q := a.ClearStatus().Quantize(unitQuad(0), rounding) // exact rounding. The integer part of a has less than 34 digits, so it never fails.
+
+	acc := big.Exact
+	if q.Status()&Inexact != 0 {
+		acc = big.Above
+		if q.Less(a) {
+			acc = big.Below
+		}
+	}
+
+	if acc != big.Exact {
+		return q.Coefficient(), acc, QuadError(Inexact)
+	}
+
+	return q.Coefficient(), acc, nil
+}
+
+// FromBigInt returns the Quad x.
+// If x has more than 34 digits, it is rounded with RoundHalfEven mode, and Inexact is set. If it is too large, Overflow is set.
+// A nil x is 0.
+//
+func FromBigInt(x *big.Int) Quad {
+
+	return NewFromBig(x, 0)
+}
+
+// ToRat returns the exact value of a as a big.Rat.
+//
+// If a is NaN or Infinity, nil and QuadError(InvalidOperation) are returned.
+//
+// The status field of a is not checked.
+//
+func (a Quad) ToRat() (*big.Rat, error) {
+
+	if !a.IsFinite() {
+		return nil, QuadError(InvalidOperation)
+	}
+
+	coef := a.Coefficient()
+
+	exp := a.GetExponent()
+	if exp >= 0 {
+		return new(big.Rat).SetInt(coef.Mul(coef, tenPow(exp))), nil
+	}
+
+	return new(big.Rat).SetFrac(coef, tenPow(-exp)), nil
+}
+
+// FromRat returns the Quad nearest to r, rounded with the rounding mode passed as argument.
+//
+//        FromRat(1/4)     returns 0.25
+//        FromRat(1/3)     returns 0.3333333333333333333333333333333333 with Inexact
+//        FromRat(5)       returns 5
+//
+// If r has a finite decimal expansion of at most 34 digits, the result is exact, and has no trailing zeros after the decimal point.
+// Else, it is correctly rounded, and Inexact is set. Overflow or Underflow is set if r is out of range.
+// A nil r is 0.
+//
+func FromRat(r *big.Rat, rounding RoundingMode) Quad {
+	var (
+		num, den, rem big.Int
+		buff          [DecquadPmax + 10]byte
+	)
+
+	if r == nil || r.Sign() == 0 {
+		return Zero()
+	}
+
+	neg := r.Sign() < 0
+	num.Abs(r.Num())
+	den.Set(r.Denom())
+
+	if r.IsInt() {
+		return fromBCD(bigToBCD(buff[:0], &num), 0, neg, rounding)
+	}
+
+	// magnitude is the decimal exponent of r, within 1 or 2
+
+	magnitude := int64(float64(num.BitLen()-den.BitLen()) * log10Of2)
+
+	if magnitude > bigMaxMag || magnitude < bigMinMag {
+		return outOfRange(magnitude > 0, neg, rounding)
+	}
+
+	// quotient num*10^k / den has at least DecquadPmax+1 digits, so that the sticky digit is beyond the rounding position
+
+	k := DecquadPmax + 3 - magnitude
+	if k >= 0 {
+		num.Mul(&num, tenPow(int32(k)))
+	} else {
+		den.Mul(&den, tenPow(int32(-k)))
+	}
+
+	num.QuoRem(&num, &den, &rem)
+
+	digits := bigToBCD(buff[:0], &num)
+
+	if rem.Sign() != 0 {
+		digits = append(digits, 1) // sticky digit, for exact rounding
+		k++
+	} else {
+		for k > 0 && digits[len(digits)-1] == 0 { // exact, remove the trailing zeros of the fractional part
+			digits = digits[:len(digits)-1]
+			k--
+		}
+	}
+
+	return fromBCD(digits, clampExp(-k), neg, rounding)
+}
+
+// outOfRange returns the result of an operation whose magnitude is far above (overflow true) or far below the range of Quad.
+// The result is Infinity, the largest Quad, 0 or the smallest Quad, depending on the rounding mode, and the status flags are set as for any other operation.
+//
+func outOfRange(overflow bool, neg bool, rounding RoundingMode) Quad {
+
+	if overflow {
+		return fromBCD([]byte{1}, 1<<30, neg, rounding)
+	}
+
+	return fromBCD([]byte{1}, -1<<30, neg, rounding)
+}
+
+// ToBigFloat returns a as a big.Float with prec bits of mantissa, rounded with big.ToNearestEven mode.
+// If prec is 0, the precision is chosen as by big.Float.SetRat, at least 64 bits.
+//
+// If the result is rounded, it is returned with QuadError(Inexact) as error. Use errors.Is(err, QuadError(Inexact)) to accept it.
+// The accuracy is the one of the big.Float, which tells if the result is exactly a, or below or above a.
+// Infinity is converted to an infinite big.Float.
+// If a is NaN, nil, big.Exact and QuadError(InvalidOperation) are returned.
+//
+// The status field of a is not checked.
+//
+func (a Quad) ToBigFloat(prec uint) (*big.Float, big.Accuracy, error) {
+
+	if a.IsNaN() {
+		return nil, big.Exact, QuadError(InvalidOperation)
+	}
+
+	z := new(big.Float).SetPrec(prec)
+
+	if a.IsInfinite() {
+		return z.SetInf(a.IsNegative()), big.Exact, nil
+	}
+
+	r, _ := a.ToRat()
+	z.SetRat(r)
+
+	if z.Acc() != big.Exact {
+		return z, z.Acc(), QuadError(Inexact)
+	}
+
+	return z, z.Acc(), nil
+}
+
+// FromBigFloat returns the Quad nearest to f, rounded with RoundHalfEven mode.
+//
+// A binary floating point number always has a finite decimal expansion, but it can have many digits, e.g. 2^-100 has 100 decimal digits.
+// If it has more than 34 digits, the result is rounded, and Inexact is set. Overflow or Underflow is set if f is out of range.
+// An infinite f is converted to Infinity. A nil f is 0.
+//
+func FromBigFloat(f *big.Float) Quad {
+
+	if f == nil || f.Sign() == 0 {
+		return Zero()
+	}
+
+	if f.IsInf() {
+		r, _ := FromString("Infinity")
+		if f.Signbit() {
+			r = r.Neg()
+		}
+		return r
+	}
+
+	// f = mant * 2^exp2, with 0.5 <= |mant| < 1. Avoid huge big.Rat for values out of range.
+
+	exp2 := f.MantExp(nil)
+	magnitude := int64(float64(exp2) * log10Of2)
+
+	if magnitude > bigMaxMag || magnitude < bigMinMag {
+		return outOfRange(magnitude > 0, f.Signbit(), RoundHalfEven)
+	}
+
+	r, _ := f.Rat(nil)
+
+	return FromRat(r, RoundHalfEven)
+}
@@ -0,0 +1,204 @@
+package decnum
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestToBigInt(t *testing.T) {
+
+	samples := []struct {
+		a        string
+		rounding RoundingMode
+		expected string
+		acc      big.Accuracy
+	}{
+		{"123", RoundHalfEven, "123", big.Exact},
+		{"-1.50E+3", RoundHalfEven, "-1500", big.Exact},
+		{"1E+40", RoundHalfEven, "10000000000000000000000000000000000000000", big.Exact},
+		{"12.00", RoundHalfEven, "12", big.Exact},
+		{"12.5", RoundHalfEven, "12", big.Below},
+		{"12.5", RoundHalfUp, "13", big.Above},
+		{"-12.1", RoundFloor, "-13", big.Below},
+		{"-12.1", RoundDown, "-12", big.Above},
+		{"0.001", RoundHalfEven, "0", big.Below},
+		{"999999999999999999999999999999999.5", RoundHalfEven, "1000000000000000000000000000000000", big.Above},
+	}
+
+	for _, s := range samples {
+		var expectedErr error
+		if s.acc != big.Exact {
+			expectedErr = QuadError(Inexact)
+		}
+
+		z, acc, err := must_quad(s.a).ToBigInt(s.rounding)
+		if z.String() != s.expected || acc != s.acc || err != expectedErr {
+			t.Fatalf("%s.ToBigInt(%s) = %s, %s, %v, expected %s, %s", s.a, s.rounding, z, acc, err, s.expected, s.acc)
+		}
+	}
+
+	for _, a := range []string{"NaN", "Inf", "-Inf"} {
+		if z, _, err := must_quad(a).ToBigInt(RoundHalfEven); z != nil || err != QuadError(InvalidOperation) {
+			t.Fatalf("%s.ToBigInt() = %s, %v, expected error", a, z, err)
+		}
+	}
+}
+
+func TestFromBigInt(t *testing.T) {
+
+	x, _ := new(big.Int).SetString("123456789012345678901234567890123456789", 10)
+
+	samples := []struct {
+		x        *big.Int
+		expected string
+		status   Status
+	}{
+		{big.NewInt(-42), "-42", 0},
+		{nil, "0", 0},
+		{x, "1.234567890123456789012345678901235E+38", Inexact},
+		{new(big.Int).Exp(big.NewInt(10), big.NewInt(7000), nil), "Infinity", Inexact | Overflow},
+	}
+
+	for _, s := range samples {
+		r := FromBigInt(s.x)
+		if r.String() != s.expected || r.Status()&(Inexact|Overflow) != s.status {
+			t.Fatalf("FromBigInt(%s) = %s, status %s, expected %s, %s", s.x, r, r.Status(), s.expected, s.status)
+		}
+	}
+}
+
+func TestToRat(t *testing.T) {
+
+	samples := []struct {
+		a        string
+		expected string
+	}{
+		{"0.25", "1/4"},
+		{"-1.50", "-3/2"},
+		{"1E+3", "1000/1"},
+		{"0", "0/1"},
+		{"1E-40", "1/10000000000000000000000000000000000000000"},
+	}
+
+	for _, s := range samples {
+		r, err := must_quad(s.a).ToRat()
+		if err != nil || r.String() != s.expected {
+			t.Fatalf("%s.ToRat() = %s, %v, expected %s", s.a, r, err, s.expected)
+		}
+	}
+
+	if r, err := must_quad("NaN").ToRat(); r != nil || err != QuadError(InvalidOperation) {
+		t.Fatalf("NaN.ToRat() = %s, %v, expected error", r, err)
+	}
+}
+
+func TestFromRat(t *testing.T) {
+
+	samples := []struct {
+		r        string
+		rounding RoundingMode
+		expected string
+		status   Status
+	}{
+		{"1/4", RoundHalfEven, "0.25", 0},
+		{"-3/2", RoundHalfEven, "-1.5", 0},
+		{"5", RoundHalfEven, "5", 0},
+		{"0", RoundHalfEven, "0", 0},
+		{"1/1000", RoundHalfEven, "0.001", 0},
+		{"1/3", RoundHalfEven, "0.3333333333333333333333333333333333", Inexact},
+		{"2/3", RoundHalfEven, "0.6666666666666666666666666666666667", Inexact},
+		{"2/3", RoundDown, "0.6666666666666666666666666666666666", Inexact},
+		{"-2/3", RoundFloor, "-0.6666666666666666666666666666666667", Inexact},
+		{"1000000/3", RoundHalfEven, "333333.3333333333333333333333333333", Inexact},
+		{"1/7000000000000000000000000000000000000000000", RoundHalfEven, "1.428571428571428571428571428571429E-43", Inexact},
+		{"12345678901234567890123456789012345/10", RoundHalfEven, "1234567890123456789012345678901234", Inexact}, // exact half, to even
+		{"12345678901234567890123456789012355/10", RoundHalfEven, "1234567890123456789012345678901236", Inexact},
+		{"12345678901234567890123456789012345/10", RoundHalfUp, "1234567890123456789012345678901235", Inexact},
+		{"1/1" + zeros(7000), RoundHalfEven, "0E-6176", Inexact | Underflow},
+		{"1" + zeros(7000) + "/3", RoundHalfEven, "Infinity", Inexact | Overflow},
+	}
+
+	for _, s := range samples {
+		r, _ := new(big.Rat).SetString(s.r)
+		q := FromRat(r, s.rounding)
+		if q.String() != s.expected || q.Status()&(Inexact|Overflow|Underflow) != s.status {
+			t.Fatalf("FromRat(%s, %s) = %s, status %s, expected %s, %s", s.r, s.rounding, q, q.Status(), s.expected, s.status)
+		}
+	}
+
+	for _, a := range []string{"123.456", "-0.001", "1E+100", "1.234567890123456789012345678901234E-200"} {
+		r, _ := must_quad(a).ToRat()
+		if q := FromRat(r, RoundHalfEven); !q.Equal(must_quad(a)) || q.Status() != 0 {
+			t.Fatalf("FromRat(%s.ToRat()) = %s, status %s", a, q, q.Status())
+		}
+	}
+}
+
+func zeros(n int) string {
+
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = '0'
+	}
+	return string(b)
+}
+
+func TestToBigFloat(t *testing.T) {
+
+	samples := []struct {
+		a        string
+		prec     uint
+		expected string
+		acc      big.Accuracy
+	}{
+		{"0.5", 53, "0.5", big.Exact},
+		{"-1.25E+2", 53, "-125", big.Exact},
+		{"0.1", 53, "0.1000000000000000055511151231257827", big.Above},
+		{"0.1", 200, "0.1", big.Above},
+		{"-0.1", 53, "-0.1000000000000000055511151231257827", big.Below},
+		{"Infinity", 53, "+Inf", big.Exact},
+		{"-Infinity", 53, "-Inf", big.Exact},
+	}
+
+	for _, s := range samples {
+		var expectedErr error
+		if s.acc != big.Exact {
+			expectedErr = QuadError(Inexact)
+		}
+
+		f, acc, err := must_quad(s.a).ToBigFloat(s.prec)
+		if f.Text('g', 34) != s.expected || acc != s.acc || err != expectedErr {
+			t.Fatalf("%s.ToBigFloat(%d) = %s, %s, %v, expected %s, %s", s.a, s.prec, f.Text('g', 34), acc, err, s.expected, s.acc)
+		}
+	}
+
+	if f, _, err := must_quad("NaN").ToBigFloat(53); f != nil || err != QuadError(InvalidOperation) {
+		t.Fatalf("NaN.ToBigFloat() = %v, %v, expected error", f, err)
+	}
+}
+
+func TestFromBigFloat(t *testing.T) {
+
+	samples := []struct {
+		f        *big.Float
+		expected string
+		status   Status
+	}{
+		{big.NewFloat(0.5), "0.5", 0},
+		{big.NewFloat(-1024), "-1024", 0},
+		{big.NewFloat(0.1), "0.1000000000000000055511151231257827", Inexact},
+		{new(big.Float).SetMantExp(big.NewFloat(1), -100), "7.888609052210118054117285652827862E-31", Inexact},
+		{new(big.Float).SetMantExp(big.NewFloat(1), 1000000), "Infinity", Inexact | Overflow},
+		{new(big.Float).SetMantExp(big.NewFloat(-1), 1000000), "-Infinity", Inexact | Overflow},
+		{new(big.Float).SetMantExp(big.NewFloat(1), -1000000), "0E-6176", Inexact | Underflow},
+		{new(big.Float).SetInf(true), "-Infinity", 0},
+		{nil, "0", 0},
+	}
+
+	for _, s := range samples {
+		q := FromBigFloat(s.f)
+		if q.String() != s.expected || q.Status()&(Inexact|Overflow|Underflow) != s.status {
+			t.Fatalf("FromBigFloat(%v) = %s, status %s, expected %s, %s", s.f, q, q.Status(), s.expected, s.status)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package decnum
+
+import (
+	"encoding/binary"
+	"math/big"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                   CBOR decimal fraction (RFC 8949)                   */
+/*                                                                      */
+/************************************************************************/
+
+// CBOR major types and tags used by decimal fractions.
+const (
+	cborUnsigned   = 0 << 5 // major type 0, unsigned integer
+	cborNegative   = 1 << 5 // major type 1, negative integer -1-n
+	cborByteString = 2 << 5 // major type 2, byte string
+	cborArray      = 4 << 5 // major type 4, array
+	cborTag        = 6 << 5 // major type 6, tag
+
+	cborTagPosBignum       = 2 // tag 2, unsigned bignum
+	cborTagNegBignum       = 3 // tag 3, negative bignum -1-n
+	cborTagDecimalFraction = 4 // tag 4, decimal fraction [exponent, mantissa]
+)
+
+// AppendCBOR appends a to dst, encoded as a CBOR decimal fraction: tag 4 followed by the array [exponent, mantissa].
+//
+// The exponent and mantissa are exactly the exponent and coefficient of a, so 1.50 is encoded as [-2, 150].
+// The mantissa is encoded as an integer if it fits in 64 bits, else as a bignum (tag 2 or 3).
+//
+// A CBOR integer or bignum has no negative zero, so the sign of a zero is lost: -0.00 is encoded as [-2, 0], as 0.00.
+//
+// An error is returned if a is Inf or NaN, which have no decimal fraction representation (InvalidOperation).
+// In this case, dst is returned unchanged.
+//
+func (a Quad) AppendCBOR(dst []byte) ([]byte, error) {
+	var mantissa big.Int
+
+	bcd, exp, neg, infNaN := a.toBCD()
+	if infNaN != 0 {
+		return dst, newError(InvalidOperation)
+	}
+
+	bcdToBig(&mantissa, bcd[:])
+
+	dst = cborAppendHead(dst, cborTag, cborTagDecimalFraction)
+	dst = cborAppendHead(dst, cborArray, 2)
+
+	if exp >= 0 {
+		dst = cborAppendHead(dst, cborUnsigned, uint64(exp))
+	} else {
+		dst = cborAppendHead(dst, cborNegative, uint64(-1-int64(exp)))
+	}
+
+	if neg {
+		mantissa.Sub(&mantissa, big.NewInt(1)) // negative values are encoded as -1-n
+	}
+
+	switch {
+	case mantissa.IsUint64() && !neg:
+		dst = cborAppendHead(dst, cborUnsigned, mantissa.Uint64())
+	case mantissa.IsUint64() && neg:
+		dst = cborAppendHead(dst, cborNegative, mantissa.Uint64())
+	default:
+		if neg {
+			dst = cborAppendHead(dst, cborTag, cborTagNegBignum)
+		} else {
+			dst = cborAppendHead(dst, cborTag, cborTagPosBignum)
+		}
+		b := mantissa.Bytes()
+		dst = cborAppendHead(dst, cborByteString, uint64(len(b)))
+		dst = append(dst, b...)
+	}
+
+	return dst, nil
+}
+
+// FromCBOR decodes a CBOR decimal fraction at the beginning of b, and returns it with the number of bytes read.
+//
+// b must start with tag 4, followed by the array [exponent, mantissa].
+// The exponent must be an integer. The mantissa can be an integer or a bignum (tag 2 or 3).
+//
+// If the mantissa has more than 34 digits, it is rounded with the rounding mode passed as argument, and Inexact is set.
+//
+// If b doesn't contain a valid decimal fraction, the result is NaN with ConversionSyntax status, and n is 0.
+//
+// This function returns result.Error() as a convenience.
+//
+func FromCBOR(b []byte, rounding RoundingMode) (result Quad, n int, err error) {
+	var (
+		mantissa big.Int
+		digits   []byte
+		exp      int64
+		ok       bool
+		major    byte
+		v        uint64
+	)
+
+	syntaxError := func() (Quad, int, error) {
+		result = NaN().SetStatusFlags(ConversionSyntax)
+		return result, 0, result.Error()
+	}
+
+	// tag 4, array of 2 items
+
+	if major, v, n, ok = cborReadHead(b, 0); !ok || major != cborTag || v != cborTagDecimalFraction {
+		return syntaxError()
+	}
+
+	if major, v, n, ok = cborReadHead(b, n); !ok || major != cborArray || v != 2 {
+		return syntaxError()
+	}
+
+	// exponent
+
+	if major, v, n, ok = cborReadHead(b, n); !ok || v > 1<<62 {
+		return syntaxError()
+	}
+
+	switch major {
+	case cborUnsigned:
+		exp = int64(v)
+	case cborNegative:
+		exp = -1 - int64(v)
+	default:
+		return syntaxError()
+	}
+
+	// mantissa
+
+	if major, v, n, ok = cborReadHead(b, n); !ok {
+		return syntaxError()
+	}
+
+	neg := false
+
+	switch major {
+	case cborUnsigned:
+		mantissa.SetUint64(v)
+	case cborNegative:
+		mantissa.SetUint64(v)
+		neg = true
+	case cborTag:
+		if v != cborTagPosBignum && v != cborTagNegBignum {
+			return syntaxError()
+		}
+		neg = v == cborTagNegBignum
+
+		if major, v, n, ok = cborReadHead(b, n); !ok || major != cborByteString || v > uint64(len(b)-n) {
+			return syntaxError()
+		}
+
+		mantissa.SetBytes(b[n : n+int(v)])
+		n += int(v)
+	default:
+		return syntaxError()
+	}
+
+	if neg {
+		mantissa.Add(&mantissa, big.NewInt(1)) // magnitude of -1-n is n+1
+	}
+
+	digits = bigToBCD(digits, &mantissa)
+	result = fromBCD(digits, clampExp(exp), neg, rounding)
+
+	return result, n, result.Error()
+}
+
+// cborAppendHead appends the head of a CBOR data item, that is, the major type and its argument.
+//
+func cborAppendHead(dst []byte, major byte, v uint64) []byte {
+
+	switch {
+	case v < 24:
+		return append(dst, major|byte(v))
+	case v <= 0xff:
+		return append(dst, major|24, byte(v))
+	case v <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(dst, major|25), uint16(v))
+	case v <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(dst, major|26), uint32(v))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, major|27), v)
+	}
+}
+
+// cborReadHead reads the head of a CBOR data item at position pos in b.
+// It returns the major type, its argument, and the position following the head.
+// Indefinite lengths are not accepted.
+//
+func cborReadHead(b []byte, pos int) (major byte, v uint64, next int, ok bool) {
+
+	if pos >= len(b) {
+		return 0, 0, pos, false
+	}
+
+	major = b[pos] & 0xe0
+	info := b[pos] & 0x1f
+	pos++
+
+	switch {
+	case info < 24:
+		return major, uint64(info), pos, true
+	case info == 24 && len(b)-pos >= 1:
+		return major, uint64(b[pos]), pos + 1, true
+	case info == 25 && len(b)-pos >= 2:
+		return major, uint64(binary.BigEndian.Uint16(b[pos:])), pos + 2, true
+	case info == 26 && len(b)-pos >= 4:
+		return major, uint64(binary.BigEndian.Uint32(b[pos:])), pos + 4, true
+	case info == 27 && len(b)-pos >= 8:
+		return major, binary.BigEndian.Uint64(b[pos:]), pos + 8, true
+	default:
+		return 0, 0, pos, false
+	}
+}
@@ -0,0 +1,70 @@
+package decnum
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func Test_cbor(t *testing.T) {
+
+	var samples = []struct {
+		a        string
+		expected string // hex
+	}{
+		{"273.15", "c48221196ab3"}, // example of RFC 8949
+		{"0", "c4820000"},
+		{"-1.5", "c482202e"},
+		{"1.5e3", "c482020f"},
+		{"18446744073709551615", "c482001bffffffffffffffff"},
+		{"-18446744073709551616", "c482003bffffffffffffffff"},
+		{"18446744073709551616", "c48200c249010000000000000000"},
+		{"-18446744073709551617", "c48200c349010000000000000000"},
+		{"1E-6176", "c48239181f01"},
+		{"9.999999999999999999999999999999999E+6144", "c4821917dfc24f01ed09bead87c0378d8e63ffffffff"},
+	}
+
+	for _, s := range samples {
+		a := must_quad(s.a)
+
+		b, err := a.AppendCBOR(nil)
+		if err != nil || hex.EncodeToString(b) != s.expected {
+			t.Fatalf("AppendCBOR(%s) = %x %v, expected %s", s.a, b, err, s.expected)
+		}
+
+		b = append(b, 0xff) // trailing byte must not be read
+		back, n, err := FromCBOR(b, RoundHalfEven)
+		if err != nil || n != len(b)-1 || back.QuadToString() != a.QuadToString() {
+			t.Fatalf("FromCBOR(%x) = %s %d %v, expected %s", b, back, n, err, s.a)
+		}
+	}
+
+	// the sign of zero is lost
+
+	b, err := must_quad("-0.00").AppendCBOR(nil)
+	if err != nil || hex.EncodeToString(b) != "c4822100" {
+		t.Fatalf("AppendCBOR(-0.00) = %x %v, expected c4822100", b, err)
+	}
+
+	if back, _, err := FromCBOR(b, RoundHalfEven); err != nil || back.QuadToString() != "0.00" {
+		t.Fatalf("FromCBOR(%x) = %s %v, expected 0.00", b, back, err)
+	}
+
+	if _, err := must_quad("Inf").AppendCBOR(nil); err == nil {
+		t.Fatal("AppendCBOR(Inf) should fail")
+	}
+
+	// bignum mantissa with more than 34 digits: 2^128 = 340282366920938463463374607431768211456
+
+	raw, _ := hex.DecodeString("c48200c2510100000000000000000000000000000000")
+	back, _, err := FromCBOR(raw, RoundHalfEven)
+	if err != nil || back.String() != "3.402823669209384634633746074317682E+38" || back.Status() != Inexact {
+		t.Fatalf("FromCBOR(%x) = %s %s %v", raw, back, back.Status(), err)
+	}
+
+	for _, bad := range []string{"", "c4", "c48100", "c5820000", "c48200c2", "c48200c24401", "c482f900000000", "c48200c4820000"} {
+		raw, _ := hex.DecodeString(bad)
+		if q, n, err := FromCBOR(raw, RoundHalfEven); err == nil || n != 0 || !q.IsNaN() {
+			t.Fatalf("FromCBOR(%s) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package decnum
+
+import (
+	"math/big"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                     coefficient and exponent                         */
+/*                                                                      */
+/************************************************************************/
+
+// New returns the Quad coefficient * 10^exponent, e.g. New(12345, -2) is 123.45.
+// It is exact, as an int64 has at most 19 digits.
+//
+// If exponent is out of range, the result is 0 or Infinity with Underflow or Overflow status, or clamped, as for any other operation.
+//
+func New(coefficient int64, exponent int32) Quad {
+	var (
+		buff [DecquadPmax]byte
+		u    uint64
+	)
+
+	neg := coefficient < 0
+	u = uint64(coefficient)
+	if neg {
+		u = -u // also correct for math.MinInt64
+	}
+
+	return fromBCD(appendChunk(buff[:0], u, false), exponent, neg, RoundHalfEven) // a chunk holds 19 digits, enough for an int64
+}
+
+// NewFromBig returns the Quad coefficient * 10^exponent.
+// If coefficient has more than 34 digits, it is rounded with RoundHalfEven mode, and Inexact is set.
+// A nil coefficient is 0.
+//
+func NewFromBig(coefficient *big.Int, exponent int32) Quad {
+	var buff [bcdMax]byte
+
+	if coefficient == nil {
+		return New(0, exponent)
+	}
+
+	return fromBCD(bigToBCD(buff[:0], coefficient), exponent, coefficient.Sign() < 0, RoundHalfEven)
+}
+
+// Coefficient returns the coefficient of a, with the sign of a, so that a is Coefficient() * 10^GetExponent().
+// E.g. the coefficient of -1.50 is -150.
+//
+// It returns nil if a is NaN or Infinity.
+//
+func (a Quad) Coefficient() *big.Int {
+
+	bcd, _, neg, infNaN := a.toBCD()
+	if infNaN != 0 {
+		return nil
+	}
+
+	z := bcdToBig(new(big.Int), bcd[:])
+	if neg {
+		z.Neg(z)
+	}
+
+	return z
+}
+
+// CoefficientDigits returns the digits of the coefficient of a, as ASCII characters '0' to '9', without leading zeros.
+// E.g. the digits of -1.50 are "150", and the digits of 0.00 are "0".
+//
+// It returns nil if a is NaN or Infinity.
+//
+func (a Quad) CoefficientDigits() []byte {
+
+	if !a.IsFinite() {
+		return nil
+	}
+
+	digits, _, _ := a.appendDigits(nil)
+	return digits
+}
+
+// Sign returns -1 if a is negative, 0 if a is zero, and +1 if a is positive. Negative zero returns 0.
+// It returns 0 if a is NaN.
+//
+func (a Quad) Sign() int {
+
+	switch {
+	case a.IsNaN() || a.IsZero():
+		return 0
+	case a.IsNegative():
+		return -1
+	default:
+		return 1
+	}
+}
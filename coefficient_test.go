@@ -0,0 +1,114 @@
+package decnum
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+
+	samples := []struct {
+		coefficient int64
+		exponent    int32
+		expected    string
+	}{
+		{12345, -2, "123.45"},
+		{-150, -2, "-1.50"},
+		{0, 0, "0"},
+		{0, -3, "0.000"},
+		{7, 3, "7E+3"},
+		{math.MaxInt64, 0, "9223372036854775807"},
+		{math.MinInt64, 0, "-9223372036854775808"},
+		{math.MinInt64, -19, "-0.9223372036854775808"},
+		{1, -6176, "1E-6176"},
+		{1, 6111, "1E+6111"},
+	}
+
+	for _, s := range samples {
+		r := New(s.coefficient, s.exponent)
+		if r.String() != s.expected || r.Error() != nil {
+			t.Fatalf("New(%d, %d) = %s, %v, expected %s", s.coefficient, s.exponent, r, r.Error(), s.expected)
+		}
+		if c := r.Coefficient(); c.Cmp(big.NewInt(s.coefficient)) != 0 || r.GetExponent() != s.exponent {
+			t.Fatalf("New(%d, %d) has coefficient %s and exponent %d", s.coefficient, s.exponent, c, r.GetExponent())
+		}
+	}
+
+	if r := New(1, 7000); !r.IsInfinite() || r.Status()&Overflow == 0 {
+		t.Fatalf("New(1, 7000) = %s, expected Infinity with Overflow", r)
+	}
+}
+
+func TestNewFromBig(t *testing.T) {
+
+	big34, _ := new(big.Int).SetString("-1234567890123456789012345678901234", 10)
+	big36, _ := new(big.Int).SetString("123456789012345678901234567890123456", 10)
+
+	samples := []struct {
+		coefficient *big.Int
+		exponent    int32
+		expected    string
+		inexact     bool
+	}{
+		{big.NewInt(12345), -2, "123.45", false},
+		{big34, -4, "-123456789012345678901234567890.1234", false},
+		{big36, 0, "1.234567890123456789012345678901235E+35", true},
+		{nil, -2, "0.00", false},
+	}
+
+	for _, s := range samples {
+		r := NewFromBig(s.coefficient, s.exponent)
+		if r.String() != s.expected || (r.Status()&Inexact != 0) != s.inexact {
+			t.Fatalf("NewFromBig(%s, %d) = %s, status %s, expected %s", s.coefficient, s.exponent, r, r.Status(), s.expected)
+		}
+	}
+
+	r := NewFromBig(big34, -4)
+	if r.Coefficient().Cmp(big34) != 0 {
+		t.Fatalf("Coefficient() = %s, expected %s", r.Coefficient(), big34)
+	}
+}
+
+func TestCoefficientAccessors(t *testing.T) {
+
+	samples := []struct {
+		a           string
+		coefficient string
+		digits      string
+		sign        int
+	}{
+		{"-1.50", "-150", "150", -1},
+		{"0.00", "0", "0", 0},
+		{"-0", "0", "0", 0},
+		{"1.23E+40", "123", "123", 1},
+		{"9999999999999999999999999999999999", "9999999999999999999999999999999999", "9999999999999999999999999999999999", 1},
+	}
+
+	for _, s := range samples {
+		a := must_quad(s.a)
+		if c := a.Coefficient(); c.String() != s.coefficient {
+			t.Fatalf("%s.Coefficient() = %s, expected %s", s.a, c, s.coefficient)
+		}
+		if d := a.CoefficientDigits(); string(d) != s.digits {
+			t.Fatalf("%s.CoefficientDigits() = %s, expected %s", s.a, d, s.digits)
+		}
+		if sign := a.Sign(); sign != s.sign {
+			t.Fatalf("%s.Sign() = %d, expected %d", s.a, sign, s.sign)
+		}
+		if r := NewFromBig(a.Coefficient(), a.GetExponent()); r.String() != a.String() {
+			t.Fatalf("NewFromBig(Coefficient(), GetExponent()) = %s, expected %s", r, a)
+		}
+	}
+
+	for _, s := range []string{"NaN", "Inf", "-Inf"} {
+		a := must_quad(s)
+		if a.Coefficient() != nil || a.CoefficientDigits() != nil {
+			t.Fatalf("%s coefficient must be nil", s)
+		}
+	}
+
+	if must_quad("-Inf").Sign() != -1 || must_quad("Inf").Sign() != 1 || NaN().Sign() != 0 {
+		t.Fatalf("Sign() of special values failed")
+	}
+}
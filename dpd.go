@@ -0,0 +1,391 @@
+package decnum
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"unsafe"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                decoding of the DPD encoding, in pure Go              */
+/*                                                                      */
+/************************************************************************/
+
+// A decQuad is 128 bits, stored in platform byte order (see nativeBigEndian):
+//
+//        bit 127          sign
+//        bits 126-122     combination field: 2 high bits of the exponent and most significant digit, or Infinity and NaN
+//        bits 121-110     exponent continuation
+//        bits 109-0       coefficient continuation, 11 declets of 10 bits, each one encoding 3 digits in Densely Packed Decimal
+//
+// See "The decNumber Library" and "A Summary of Densely Packed Decimal encoding" by Mike Cowlishaw.
+
+const (
+	dpdBias = 6176 // exponent bias of decQuad
+
+	dpdSpecialMask = 0x78 << 56 // DFISSPECIAL() in decNumberLocal.h: Infinity or NaN if these bits are all set
+	dpdNaNMask     = 0x7c << 56 // DFISNAN(): NaN or sNaN if these bits are all set
+
+	dpdFinite = 0 // kinds of values returned by decode
+	dpdInf    = 1
+	dpdNaN    = 2
+	dpdSNaN   = 3
+)
+
+// dpd2bcd converts a declet to its 3 digits, most significant first. All the 1024 declets are decoded, including the non-canonical ones.
+//
+var dpd2bcd = func() (table [1024][3]byte) {
+
+	for d := range table {
+		b := func(i uint) byte { return byte(d>>i) & 1 }
+
+		// bits b9..b0 are named p q r s t u v w x y
+
+		pqr := byte(d>>7) & 7
+		stu := byte(d>>4) & 7
+		wxy := byte(d) & 7
+		p, q, r := b(9), b(8), b(7)
+		s, t, u := b(6), b(5), b(4)
+		y := b(0)
+
+		var d2, d1, d0 byte
+
+		switch {
+		case b(3) == 0: // v == 0: three small digits
+			d2, d1, d0 = pqr, stu, wxy
+		case b(2) == 0 && b(1) == 0:
+			d2, d1, d0 = pqr, stu, 8+y
+		case b(2) == 0 && b(1) == 1:
+			d2, d1, d0 = pqr, 8+u, s<<2|t<<1|y
+		case b(2) == 1 && b(1) == 0:
+			d2, d1, d0 = 8+r, stu, p<<2|q<<1|y
+		case s == 0 && t == 0:
+			d2, d1, d0 = 8+r, 8+u, p<<2|q<<1|y
+		case s == 0 && t == 1:
+			d2, d1, d0 = 8+r, p<<2|q<<1|u, 8+y
+		case s == 1 && t == 0:
+			d2, d1, d0 = pqr, 8+u, 8+y
+		default:
+			d2, d1, d0 = 8+r, 8+u, 8+y
+		}
+
+		table[d] = [3]byte{d2, d1, d0}
+	}
+
+	return table
+}()
+
+// dpd2bin converts a declet to its value, from 0 to 999.
+//
+var dpd2bin = func() (table [1024]uint16) {
+
+	for d, bcd := range dpd2bcd {
+		table[d] = uint16(bcd[0])*100 + uint16(bcd[1])*10 + uint16(bcd[2])
+	}
+
+	return table
+}()
+
+// words returns the 128 bits of a, as two uint64.
+//
+func (a Quad) words() (hi uint64, lo uint64) {
+
+	b := (*[DecquadBytes]byte)(unsafe.Pointer(&a.val))
+
+	if nativeBigEndian {
+		return binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+	}
+
+	return binary.LittleEndian.Uint64(b[8:]), binary.LittleEndian.Uint64(b[:8])
+}
+
+// dpdExponent returns the exponent of a finite decQuad, whose high 64 bits are hi.
+//
+func dpdExponent(hi uint64) int32 {
+
+	comb := hi >> 58 & 0x1f
+	econ := hi >> 46 & 0xfff
+
+	expHigh := comb >> 3 // 2 high bits of the exponent
+	if expHigh == 3 {
+		expHigh = comb >> 1 & 3
+	}
+
+	return int32(expHigh<<12|econ) - dpdBias
+}
+
+// dpdIsZero returns true if hi and lo encode a finite decQuad with a zero coefficient, as DFISZERO() in decNumberLocal.h.
+//
+func dpdIsZero(hi uint64, lo uint64) bool {
+
+	return lo == 0 && hi&0x1c003fffffffffff == 0 && hi&0x6000000000000000 != 0x6000000000000000
+}
+
+// dpdCoefficient returns the coefficient of a finite decQuad as two integers, the 16 most significant digits and the 18 least significant digits.
+//
+func dpdCoefficient(hi uint64, lo uint64) (high uint64, low uint64) {
+
+	comb := hi >> 58 & 0x1f
+
+	high = comb & 7 // most significant digit
+	if comb>>3 == 3 {
+		high = 8 + comb&1
+	}
+
+	high = high*1000 + uint64(dpd2bin[hi>>36&0x3ff])
+	high = high*1000 + uint64(dpd2bin[hi>>26&0x3ff])
+	high = high*1000 + uint64(dpd2bin[hi>>16&0x3ff])
+	high = high*1000 + uint64(dpd2bin[hi>>6&0x3ff])
+	high = high*1000 + uint64(dpd2bin[(hi<<4|lo>>60)&0x3ff])
+
+	for shift := 50; shift >= 0; shift -= 10 {
+		low = low*1000 + uint64(dpd2bin[lo>>uint(shift)&0x3ff])
+	}
+
+	return high, low
+}
+
+// decode returns the fields of a, decoded in Go without cgo call.
+//
+// digits is the coefficient, one digit per byte, most significant digit first. For NaN, it is the payload, and for Infinity, it is not set.
+// exp is the exponent of a finite number, and signed is the sign bit, also set for -0.
+//
+func (a Quad) decode() (digits [DecquadPmax]byte, exp int32, signed bool, kind int) {
+
+	hi, lo := a.words()
+
+	signed = hi>>63 != 0
+	comb := hi >> 58 & 0x1f
+	econ := hi >> 46 & 0xfff
+
+	switch {
+	case comb>>1 != 0xf:
+		digits[0] = byte(comb & 7) // most significant digit
+		if comb>>3 == 3 {
+			digits[0] = byte(8 + comb&1)
+		}
+		exp = dpdExponent(hi)
+	case comb&1 == 0:
+		return digits, 0, signed, dpdInf
+	case econ>>11 != 0:
+		kind = dpdSNaN
+	default:
+		kind = dpdNaN
+	}
+
+	declets := [11]uint64{ // declet 0 is the most significant
+		hi >> 36, hi >> 26, hi >> 16, hi >> 6, hi<<4 | lo>>60,
+		lo >> 50, lo >> 40, lo >> 30, lo >> 20, lo >> 10, lo,
+	}
+
+	for k, declet := range declets {
+		d := &dpd2bcd[declet&0x3ff]
+		digits[1+3*k], digits[2+3*k], digits[3+3*k] = d[0], d[1], d[2]
+	}
+
+	return digits, exp, signed, kind
+}
+
+// dpdRank returns the position of a decQuad, which is not NaN, in the order of numbers: -2 for -Infinity, -1 for negative, 0 for zero, 1 for positive, 2 for +Infinity.
+//
+func dpdRank(hi uint64, lo uint64) int {
+
+	rank := 1
+
+	switch {
+	case hi&dpdNaNMask == dpdSpecialMask:
+		rank = 2
+	case dpdIsZero(hi, lo):
+		return 0
+	}
+
+	if hi>>63 != 0 {
+		return -rank
+	}
+
+	return rank
+}
+
+// dpdCompareMagnitude compares the absolute values of two finite non-zero decQuads, and returns -1, 0 or +1.
+//
+func dpdCompareMagnitude(hiA uint64, loA uint64, hiB uint64, loB uint64) int {
+
+	highA, lowA := dpdCoefficient(hiA, loA)
+	highB, lowB := dpdCoefficient(hiB, loB)
+	expA, expB := dpdExponent(hiA), dpdExponent(hiB)
+
+	if expA != expB {
+		adjustedA := expA + dpdDigits(highA, lowA) // exponent of the most significant digit, plus one
+		adjustedB := expB + dpdDigits(highB, lowB)
+
+		if adjustedA != adjustedB {
+			return compareInt32(adjustedA, adjustedB)
+		}
+
+		// the coefficient with the largest exponent has fewer digits, and is aligned on the other one. Both have at most 34 digits, and fit in 128 bits.
+
+		highA, lowA = to128(highA, lowA)
+		highB, lowB = to128(highB, lowB)
+
+		for ; expA > expB; expA-- {
+			highA, lowA = mul10(highA, lowA)
+		}
+		for ; expB > expA; expB-- {
+			highB, lowB = mul10(highB, lowB)
+		}
+	}
+
+	if highA != highB {
+		return compareUint64(highA, highB)
+	}
+
+	return compareUint64(lowA, lowB)
+}
+
+// dpdDigits returns the number of digits of the coefficient returned by dpdCoefficient, without leading zeros.
+//
+func dpdDigits(high uint64, low uint64) int32 {
+
+	n, v := int32(0), low
+	if high != 0 {
+		n, v = 18, high
+	}
+
+	for n++; v >= 10; n++ {
+		v /= 10
+	}
+
+	return n
+}
+
+// to128 returns the 128 bits integer high * 10^18 + low, as two uint64.
+//
+func to128(high uint64, low uint64) (uint64, uint64) {
+
+	h, l := bits.Mul64(high, 1e18)
+	l, carry := bits.Add64(l, low, 0)
+
+	return h + carry, l
+}
+
+// mul10 returns the 128 bits integer h:l multiplied by 10. It must not overflow.
+//
+func mul10(h uint64, l uint64) (uint64, uint64) {
+
+	carry, l := bits.Mul64(l, 10)
+
+	return h*10 + carry, l
+}
+
+// compareUint64 returns -1, 0 or +1 if a is less than, equal to, or greater than b.
+//
+func compareUint64(a uint64, b uint64) int {
+
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareInt32 returns -1, 0 or +1 if a is less than, equal to, or greater than b.
+//
+func compareInt32(a int32, b int32) int {
+
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// appendSci appends a to dst, as the C function decQuadToString(), except that negative zero has no sign, as in C.mdq_QuadToString().
+// The number is written in scientific notation if the exponent is positive, or if the number is smaller than 1E-6.
+//
+func (a Quad) appendSci(dst []byte) []byte {
+	var buff [DecquadPmax]byte
+
+	digits, exp, signed, kind := a.decode()
+
+	// coefficient, without leading zeros, or payload of NaN
+
+	coef := buff[:0]
+	for _, d := range digits {
+		if d != 0 || len(coef) > 0 {
+			coef = append(coef, '0'+d)
+		}
+	}
+
+	if signed && (kind != dpdFinite || len(coef) > 0) { // no sign for zero
+		dst = append(dst, '-')
+	}
+
+	switch kind {
+	case dpdInf:
+		return append(dst, "Infinity"...)
+	case dpdSNaN:
+		return append(append(dst, "sNaN"...), coef...)
+	case dpdNaN:
+		return append(append(dst, "NaN"...), coef...)
+	}
+
+	if len(coef) == 0 {
+		coef = append(coef, '0')
+	}
+
+	pre := int32(len(coef)) + exp // number of digits before the decimal point
+
+	switch {
+	case exp > 0 || pre < -5: // d.dddE+nn
+		dst = append(dst, coef[0])
+		if len(coef) > 1 {
+			dst = append(append(dst, '.'), coef[1:]...)
+		}
+		dst = append(dst, 'E')
+		if e := pre - 1; e < 0 {
+			dst = appendInt32(append(dst, '-'), -e)
+		} else {
+			dst = appendInt32(append(dst, '+'), e)
+		}
+
+	case pre > 0: // ddd.ddd
+		dst = append(dst, coef[:pre]...)
+		if int(pre) < len(coef) {
+			dst = append(append(dst, '.'), coef[pre:]...)
+		}
+
+	default: // 0.000ddd
+		dst = append(dst, "0."...)
+		for ; pre < 0; pre++ {
+			dst = append(dst, '0')
+		}
+		dst = append(dst, coef...)
+	}
+
+	return dst
+}
+
+// appendInt32 appends the decimal representation of v >= 0 to dst.
+//
+func appendInt32(dst []byte, v int32) []byte {
+	var buff [10]byte
+
+	i := len(buff)
+	for {
+		i--
+		buff[i] = '0' + byte(v%10)
+		v /= 10
+		if v == 0 {
+			break
+		}
+	}
+
+	return append(dst, buff[i:]...)
+}
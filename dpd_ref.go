@@ -0,0 +1,58 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+/************************************************************************/
+/*                                                                      */
+/*             reference implementations in C, for the tests            */
+/*                                                                      */
+/************************************************************************/
+
+// The predicates, GetExponent and Compare are computed in Go from the DPD encoding, in dpd.go.
+// This file exists only as the C reference for TestCompareRandom: it calls the C functions of decNumber which they replace, so that the test can check that both give the same results.
+// It is not used by the package itself.
+
+// quadPredicates contains the results of the predicates and of GetExponent for a Quad.
+//
+type quadPredicates struct {
+	finite   bool
+	infinite bool
+	nan      bool
+	positive bool
+	zero     bool
+	negative bool
+	exponent int32
+}
+
+// predicates returns the results of the predicates and of GetExponent for a, computed in Go.
+//
+func (a Quad) predicates() quadPredicates {
+
+	return quadPredicates{a.IsFinite(), a.IsInfinite(), a.IsNaN(), a.IsPositive(), a.IsZero(), a.IsNegative(), a.GetExponent()}
+}
+
+// predicatesC returns the results of the predicates and of GetExponent for a, computed by the C functions of decNumber.
+//
+func (a Quad) predicatesC() quadPredicates {
+
+	return quadPredicates{
+		finite:   C.mdq_is_finite(a.val) != 0,
+		infinite: C.mdq_is_infinite(a.val) != 0,
+		nan:      C.mdq_is_nan(a.val) != 0,
+		positive: C.mdq_is_positive(a.val) != 0,
+		zero:     C.mdq_is_zero(a.val) != 0,
+		negative: C.mdq_is_negative(a.val) != 0,
+		exponent: int32(C.mdq_get_exponent(a.val)),
+	}
+}
+
+// compareC compares a and b as Compare, with the C function decQuadCompare.
+//
+func (a Quad) compareC(b Quad) CmpFlag {
+
+	return CmpFlag(C.mdq_compare(C.struct_Quad(a), C.struct_Quad(b)))
+}
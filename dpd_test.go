@@ -0,0 +1,268 @@
+package decnum
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"testing"
+)
+
+// quadFromWords returns the Quad whose 128 bits are hi and lo, as encoded by MarshalBinary.
+func quadFromWords(t *testing.T, hi uint64, lo uint64) Quad {
+	var a Quad
+
+	data := []byte{binaryVersion1, 0}
+	data = binary.BigEndian.AppendUint64(data, hi)
+	data = binary.BigEndian.AppendUint64(data, lo)
+
+	if err := a.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary(%016x%016x) failed: %v", hi, lo, err)
+	}
+
+	return a
+}
+
+// appendQuadReference is the former implementation of AppendQuad, which decodes the digits with C.
+func appendQuadReference(dst []byte, a Quad) []byte {
+
+	bcd, exp, neg, infNaN := a.toBCD()
+
+	if exp > 0 || exp < -DecquadPmax || infNaN != 0 {
+		return append(dst, a.QuadToString()...)
+	}
+
+	if neg {
+		dst = append(dst, '-')
+	}
+
+	integral := bcd[:DecquadPmax+exp]
+	for len(integral) > 0 && integral[0] == 0 {
+		integral = integral[1:]
+	}
+
+	if len(integral) == 0 {
+		dst = append(dst, '0')
+	}
+
+	for _, d := range integral {
+		dst = append(dst, '0'+d)
+	}
+
+	if exp < 0 {
+		dst = append(dst, '.')
+		for _, d := range bcd[DecquadPmax+exp:] {
+			dst = append(dst, '0'+d)
+		}
+	}
+
+	return dst
+}
+
+func checkDecode(t *testing.T, a Quad) {
+
+	digits, exp, signed, kind := a.decode()
+	bcd, bcdExp, neg, infNaN := a.toBCD()
+
+	if kind == dpdFinite {
+		if infNaN != 0 || digits != bcd || exp != bcdExp || neg != (signed && !a.IsZero()) {
+			t.Fatalf("decode(%x) = %v %d %t, expected %v %d %t", a.Bytes(), digits, exp, signed, bcd, bcdExp, neg)
+		}
+
+		if e := a.GetExponent(); e != bcdExp {
+			t.Fatalf("GetExponent(%s) = %d, expected %d", a.QuadToString(), e, bcdExp)
+		}
+
+	} else {
+		if infNaN == 0 || (kind == dpdInf) != a.IsInfinite() || (kind != dpdInf) != a.IsNaN() {
+			t.Fatalf("decode(%x) returns kind %d for %s", a.Bytes(), kind, a.QuadToString())
+		}
+
+		if e := a.GetExponent(); e != ExpNaN && e != ExpSignalingNaN && e&^0x02000000 != ExpInf { // the sNaN bit can be set for Infinity
+			t.Fatalf("GetExponent(%s) = %x, expected a special value", a.QuadToString(), e)
+		}
+	}
+
+	if s, expected := a.String(), string(appendQuadReference(nil, a)); s != expected {
+		t.Fatalf("String(%x) = %s, expected %s", a.Bytes(), s, expected)
+	}
+}
+
+func TestDecodeDeclets(t *testing.T) {
+
+	const exp0 = uint64(1)<<61 | uint64(0x820)<<46 // exponent 0 (6176 biased), most significant digit 0
+
+	for d := uint64(0); d < 1024; d++ {
+		for shift := uint(0); shift <= 100; shift += 10 {
+			hi, lo := exp0, d<<shift
+			if shift >= 64 {
+				hi |= d << (shift - 64)
+			} else {
+				hi |= d >> (64 - shift) // declet straddling lo and hi
+			}
+
+			checkDecode(t, quadFromWords(t, hi, lo))
+			checkDecode(t, quadFromWords(t, hi|1<<63|7<<58, lo)) // negative, most significant digit 7
+			checkDecode(t, quadFromWords(t, hi|0x1f<<58, lo))    // most significant digit 9, exponent 6176+2*4096
+		}
+	}
+}
+
+func TestDecodeRandom(t *testing.T) {
+
+	r := rand.New(rand.NewSource(47))
+
+	for i := 0; i < 200000; i++ {
+		checkDecode(t, quadFromWords(t, r.Uint64(), r.Uint64())) // any encoding, including non-canonical, NaN and Infinity
+	}
+
+	for i := 0; i < 100000; i++ {
+		a := New(r.Int63()>>uint(r.Intn(63)), int32(r.Intn(50)-42))
+		if r.Intn(2) == 0 {
+			a = a.Neg()
+		}
+		checkDecode(t, a)
+		checkDecode(t, a.Mul(a).Mul(a)) // up to 34 digits
+	}
+}
+
+func TestString(t *testing.T) {
+
+	samples := []struct {
+		s        string
+		expected string
+	}{
+		{"0", "0"},
+		{"-0", "0"},
+		{"-0.000", "0.000"},
+		{"0E+3", "0E+3"},
+		{"-0E-40", "0E-40"},
+		{"123.4500", "123.4500"},
+		{"-0.00000012", "-0.00000012"},
+		{"1E-34", "0.0000000000000000000000000000000001"},
+		{"1E-35", "1E-35"},
+		{"-1.5E-36", "-1.5E-36"},
+		{"12E+2", "1.2E+3"},
+		{"1E+6144", "1.000000000000000000000000000000000E+6144"}, // clamped,
+		{"9.999999999999999999999999999999999E+6144", "9.999999999999999999999999999999999E+6144"},
+		{"1E-6176", "1E-6176"},
+		{"Infinity", "Infinity"},
+		{"-Infinity", "-Infinity"},
+		{"NaN", "NaN"},
+		{"-NaN", "-NaN"},
+		{"NaN123", "NaN123"},
+		{"-sNaN0045", "-sNaN45"},
+	}
+
+	for _, s := range samples {
+		a := must_quad(s.s)
+		if r := a.String(); r != s.expected {
+			t.Fatalf("String(%s) = %s, expected %s", s.s, r, s.expected)
+		}
+	}
+}
+
+func TestStringAllocations(t *testing.T) {
+	var buff [DecquadString]byte
+
+	inputs := []Quad{must_quad("-12345.6789"), must_quad("1.5E-40"), must_quad("-Infinity"), must_quad("sNaN123")}
+
+	n := testing.AllocsPerRun(100, func() {
+		for _, a := range inputs {
+			AppendQuad(buff[:0], a)
+			a.GetExponent()
+		}
+	})
+
+	if n != 0 {
+		t.Fatalf("AppendQuad allocates %v times, expected 0", n)
+	}
+}
+
+func TestCompare(t *testing.T) {
+
+	samples := []struct {
+		a        string
+		b        string
+		expected CmpFlag
+	}{
+		{"0", "-0", CmpEqual},
+		{"0E+10", "-0.000", CmpEqual},
+		{"1.0", "1.00", CmpEqual},
+		{"1.5", "1.50", CmpEqual},
+		{"12.34", "12.35", CmpLess},
+		{"-12.34", "-12.35", CmpGreater},
+		{"1E+1", "9", CmpGreater},
+		{"9.99", "10", CmpLess},
+		{"123E-2", "1.2300001", CmpLess},
+		{"-1E-6176", "0", CmpLess},
+		{"1E+6144", "Infinity", CmpLess},
+		{"-Infinity", "-9.999999999999999999999999999999999E+6144", CmpLess},
+		{"Infinity", "Infinity", CmpEqual},
+		{"-Infinity", "Infinity", CmpLess},
+		{"NaN", "1", CmpNaN},
+		{"1", "-sNaN", CmpNaN},
+		{"NaN", "NaN", CmpNaN},
+	}
+
+	for _, s := range samples {
+		a, b := must_quad(s.a), must_quad(s.b)
+
+		if r := a.Compare(b); r != s.expected {
+			t.Fatalf("Compare(%s, %s) = %s, expected %s", s.a, s.b, r, s.expected)
+		}
+	}
+}
+
+func TestCompareRandom(t *testing.T) {
+
+	r := rand.New(rand.NewSource(48))
+
+	random := func() Quad {
+		switch r.Intn(4) {
+		case 0:
+			return quadFromWords(t, r.Uint64(), r.Uint64()) // any encoding
+		case 1:
+			return quadFromWords(t, r.Uint64()&^(0x3fff<<49)|uint64(r.Intn(4))<<49|0x2<<59, r.Uint64()) // exponent close to 0
+		default:
+			return New(r.Int63n(2000)-1000, int32(r.Intn(4)-2)) // many equal values with different exponents
+		}
+	}
+
+	for i := 0; i < 500000; i++ {
+		a, b := random(), random()
+
+		if c, expected := a.Compare(b), a.compareC(b); c != expected {
+			t.Fatalf("Compare(%s, %s) = %s, expected %s", a.QuadToString(), b.QuadToString(), c, expected)
+		}
+
+		if p, expected := a.predicates(), a.predicatesC(); p != expected {
+			t.Fatalf("predicates of %s = %+v, expected %+v", a.QuadToString(), p, expected)
+		}
+	}
+}
+
+func BenchmarkCompare(b *testing.B) {
+
+	x, y := must_quad("12345.6789"), must_quad("12345.6790")
+
+	for i := 0; i < b.N; i++ {
+		x.Compare(y)
+	}
+}
+
+func BenchmarkCompareExponents(b *testing.B) {
+
+	x, y := must_quad("12345.6789"), must_quad("12345.67")
+
+	for i := 0; i < b.N; i++ {
+		x.Compare(y)
+	}
+}
+
+func BenchmarkIsZero(b *testing.B) {
+
+	x := must_quad("12345.6789")
+
+	for i := 0; i < b.N; i++ {
+		x.IsZero()
+	}
+}
@@ -0,0 +1,874 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+import (
+	"math"
+	"math/bits"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                 conversion to and from float64 and float32           */
+/*                                                                      */
+/************************************************************************/
+
+// binaryFormat describes an IEEE 754 binary floating point format. A finite number is mant * 2^exp2.
+//
+type binaryFormat struct {
+	prec   int32 // number of bits of mantissa, including the implicit bit
+	emin   int32 // exp2 of the subnormals and of the smallest normals
+	emax   int32 // exp2 of the largest finite numbers
+	maxAdj int32 // a Quad with a larger adjusted exponent surely overflows
+	minAdj int32 // a Quad with a smaller adjusted exponent surely rounds to 0
+}
+
+var (
+	float64Format = binaryFormat{prec: 53, emin: -1074, emax: 971, maxAdj: 308, minAdj: -325}
+	float32Format = binaryFormat{prec: 24, emin: -149, emax: 104, maxAdj: 38, minAdj: -46}
+)
+
+var g_infinity Quad = quad_for_varinit("Infinity") // a constant Quad with value Infinity. It runs BEFORE init().
+
+// toBinary rounds a to the nearest number of the binary format f, ties to even, and returns it as mant * 2^exp2.
+// ok is false if a overflows. a must be finite.
+//
+// An approximation is computed with float64 operations, and it is corrected by exact comparisons with the midpoints between binary numbers.
+// If the coefficient and the power of 10 are exact float64 numbers, a single float64 operation is correctly rounded, and no correction is needed.
+//
+func (a Quad) toBinary(f binaryFormat) (mant uint64, exp2 int32, neg bool, ok bool) {
+	var c uint64
+
+	bcd, exp, neg, _ := a.toBCD()
+
+	i := 0
+	for i < DecquadPmax && bcd[i] == 0 {
+		i++
+	}
+
+	if i == DecquadPmax { // zero
+		return 0, f.emin, false, true
+	}
+
+	adjusted := exp + int32(DecquadPmax-i) - 1
+
+	switch {
+	case adjusted > f.maxAdj:
+		return 0, 0, neg, false
+	case adjusted < f.minAdj:
+		return 0, f.emin, neg, true
+	}
+
+	end := i + 19 // 19 digits always fit in an uint64
+	if end > DecquadPmax {
+		end = DecquadPmax
+	}
+
+	for _, d := range bcd[i:end] {
+		c = c*10 + uint64(d)
+	}
+
+	e10 := int(exp) + DecquadPmax - end
+
+	exact := end == DecquadPmax && c <= 1<<53 && e10 >= -22 && e10 <= 22 // 10^22 is the largest power of 10 exactly representable
+
+	v := float64(c)
+	switch {
+	case exact && e10 < 0:
+		v /= math.Pow10(-e10)
+	case exact:
+		v *= math.Pow10(e10)
+	case e10 < -300: // math.Pow10 returns 0 below 1E-323
+		v *= 1e-300
+		v *= math.Pow10(e10 + 300)
+	default:
+		v *= math.Pow10(e10)
+	}
+
+	mant, exp2 = f.split(v)
+
+	if exact && f == float64Format {
+		return mant, exp2, neg, true
+	}
+
+	d := newDecimalValue(bcd[i:], exp)
+	mant, exp2, ok = f.correct(&d, mant, exp2)
+
+	return mant, exp2, neg, ok
+}
+
+// correct rounds the decimal value d to the nearest number of the format f, ties to even, and returns it as mant * 2^exp2.
+// ok is false if d rounds to infinity.
+//
+// mant and exp2 passed as arguments are an approximation of the result, which must be a valid number of the format.
+// It is corrected by comparing d exactly with the midpoints between consecutive binary numbers, so it should be only a few units off.
+//
+func (f binaryFormat) correct(d *decimalValue, mant uint64, exp2 int32) (uint64, int32, bool) {
+
+	mantMin := uint64(1) << uint(f.prec-1) // smallest mantissa of a normal number
+	mantMax := uint64(1)<<uint(f.prec) - 1
+	moved := false
+
+	// go up while d is above the midpoint between the current number and the next one
+
+	for {
+		cmp := d.compare(2*mant+1, exp2-1)
+		if cmp < 0 || cmp == 0 && mant%2 == 0 {
+			break
+		}
+
+		if mant == mantMax && exp2 == f.emax {
+			return 0, 0, false
+		}
+
+		mant++
+		if mant > mantMax {
+			mant = mantMin
+			exp2++
+		}
+		moved = true
+	}
+
+	// go down while d is below the midpoint between the previous number and the current one
+
+	for !moved && mant > 0 {
+		predMant, predExp2 := mant-1, exp2
+		if mant == mantMin && exp2 > f.emin {
+			predMant, predExp2 = mantMax, exp2-1
+		}
+
+		cmp := d.compare(2*predMant+1, predExp2-1)
+		if cmp > 0 || cmp == 0 && mant%2 == 0 {
+			break
+		}
+
+		mant, exp2 = predMant, predExp2
+	}
+
+	return mant, exp2, true
+}
+
+// decimalValue is a positive decimal number c * 10^e, prepared for exact comparisons with binary numbers m * 2^k.
+// As 10^e is 5^e * 2^e, c * 10^e is compared with m * 2^k as num * 2^e with m * den * 2^k.
+//
+type decimalValue struct {
+	num nat   // c * 5^e if e >= 0, else c
+	den nat   // 1 if e >= 0, else 5^-e
+	exp int32 // e
+}
+
+// newDecimalValue returns the decimalValue digits * 10^exp. digits has at most DecquadPmax digits, most significant first.
+// The power of 5 is computed once, and not for each comparison.
+//
+func newDecimalValue(digits []byte, exp int32) (d decimalValue) {
+
+	d.num.setUint128(digitsToUint128(digits))
+	d.den.setUint64(1)
+	d.exp = exp
+
+	if exp >= 0 {
+		d.num.mulPow5(exp)
+	} else {
+		d.den.mulPow5(-exp)
+	}
+
+	return d
+}
+
+// compare returns -1, 0 or +1 if d is less than, equal to, or greater than m * 2^k.
+//
+func (d *decimalValue) compare(m uint64, k int32) int {
+
+	x := d.num
+	y := d.den
+	y.mulWord(m)
+
+	if d.exp > k {
+		x.shl(uint(d.exp - k))
+	} else {
+		y.shl(uint(k - d.exp))
+	}
+
+	return x.cmp(&y)
+}
+
+// split returns the number of format f nearest to |v|, within one unit, as mant * 2^exp2.
+//
+func (f binaryFormat) split(v float64) (mant uint64, exp2 int32) {
+
+	v = math.Abs(v)
+
+	switch {
+	case v == 0:
+		return 0, f.emin
+	case math.IsInf(v, 0):
+		return 1<<f.prec - 1, f.emax
+	}
+
+	frac, e := math.Frexp(v) // v = frac * 2^e, with 0.5 <= frac < 1
+	mant = uint64(math.Ldexp(frac, int(f.prec)))
+	exp2 = int32(e) - f.prec
+
+	switch {
+	case exp2 < f.emin: // subnormal
+		if shift := f.emin - exp2; shift < 64 {
+			mant >>= uint(shift)
+		} else {
+			mant = 0
+		}
+		exp2 = f.emin
+	case exp2 > f.emax:
+		mant, exp2 = 1<<f.prec-1, f.emax
+	}
+
+	return mant, exp2
+}
+
+// ToFloat32 returns a rounded to the nearest float32, ties to even.
+//
+// NaN returns NaN, and Infinity returns +Inf or -Inf.
+// If a is too large for a float32, NaN and QuadError(InvalidOperation) are returned, as for ToFloat64.
+//
+// The status field of a is not checked.
+// It is computed in Go with fixed size integers, and it doesn't allocate memory.
+//
+func (a Quad) ToFloat32() (float32, error) {
+
+	switch {
+	case a.IsNaN():
+		return float32(math.NaN()), nil
+	case a.IsInfinite():
+		return float32(math.Inf(a.infSign())), nil
+	}
+
+	mant, exp2, neg, ok := a.toBinary(float32Format)
+	if !ok {
+		return float32(math.NaN()), QuadError(InvalidOperation)
+	}
+
+	v := float32(math.Ldexp(float64(mant), int(exp2))) // exact
+	if neg {
+		v = -v
+	}
+
+	return v, nil
+}
+
+// infSign returns -1 for -Infinity, and 1 else.
+//
+func (a Quad) infSign() int {
+
+	if a.IsNegative() {
+		return -1
+	}
+
+	return 1
+}
+
+// float64Parts returns |f| as mant * 2^exp2, with mant < 2^53, and the sign of f. f must be finite.
+//
+func float64Parts(f float64) (mant uint64, exp2 int32, neg bool) {
+
+	b := math.Float64bits(f)
+
+	mant = b & (1<<52 - 1)
+	exp2 = int32(b >> 52 & 0x7ff)
+
+	if exp2 == 0 { // subnormal
+		exp2 = -1074
+	} else {
+		mant |= 1 << 52
+		exp2 -= 1075
+	}
+
+	return mant, exp2, b>>63 != 0
+}
+
+// float32Parts returns |f| as mant * 2^exp2, with mant < 2^24, and the sign of f. f must be finite.
+//
+func float32Parts(f float32) (mant uint64, exp2 int32, neg bool) {
+
+	b := math.Float32bits(f)
+
+	mant = uint64(b & (1<<23 - 1))
+	exp2 = int32(b >> 23 & 0xff)
+
+	if exp2 == 0 { // subnormal
+		exp2 = -149
+	} else {
+		mant |= 1 << 23
+		exp2 -= 150
+	}
+
+	return mant, exp2, b>>31 != 0
+}
+
+// FromFloat64Exact returns the Quad nearest to the exact value of f.
+//
+// A float64 is a binary number, and its exact decimal expansion can have up to 767 significant digits, e.g. 0.1 is 0.1000000000000000055511151231257827021181583404541015625.
+// It is rounded to 34 digits with RoundHalfEven mode, and Inexact is set if digits are lost.
+//
+//        FromFloat64Exact(0.1)       returns 0.1000000000000000055511151231257827, with Inexact
+//        FromFloat64Exact(0.375)     returns 0.375
+//        FromFloat64Exact(1e23)      returns 99999999999999991611392
+//
+// If f is NaN or Inf, NaN or Infinity is returned with QuadError(InvalidOperation) as error.
+// Else, the error is nil.
+//
+// The digits are computed in Go with fixed size integers, and it doesn't allocate memory.
+//
+func FromFloat64Exact(f float64) (Quad, error) {
+	var buff [DecquadPmax + 2]byte
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fromFloatSpecial(f)
+	}
+
+	mant, exp2, neg := float64Parts(f)
+	if mant == 0 {
+		return fromUint128(0, 0, 0, neg), nil
+	}
+
+	digits, k := exactDigits(buff[:0], mant, exp2)
+
+	hi, lo := digitsToUint128(digits)
+
+	return fromUint128(hi, lo, k-int32(len(digits)), neg), nil
+}
+
+// FromFloat32Exact returns the Quad nearest to the exact value of f.
+// It is the same as FromFloat64Exact(float64(f)), as the conversion to float64 is exact.
+//
+func FromFloat32Exact(f float32) (Quad, error) {
+
+	return FromFloat64Exact(float64(f))
+}
+
+// FromFloat64Shortest returns the Quad with the fewest digits that converts back to f, as written by strconv.FormatFloat(f, 'g', -1, 64).
+// The result is always exact, as it has at most 17 digits.
+//
+//        FromFloat64Shortest(0.1)       returns 0.1
+//        FromFloat64Shortest(100)       returns 100
+//        FromFloat64Shortest(1e100)     returns 1E+100
+//
+// An integer is returned with exponent 0 if it has at most 34 digits, e.g. 100 rather than 1E+2.
+//
+// If f is NaN or Inf, NaN or Infinity is returned with QuadError(InvalidOperation) as error.
+// Else, the error is nil.
+//
+// The digits are computed in Go with fixed size integers, without string conversion, and it doesn't allocate memory.
+//
+func FromFloat64Shortest(f float64) (Quad, error) {
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fromFloatSpecial(f)
+	}
+
+	mant, exp2, neg := float64Parts(f)
+
+	return fromFloatShortest(mant, exp2, neg, float64Format), nil
+}
+
+// FromFloat32Shortest returns the Quad with the fewest digits that converts back to f, as written by strconv.FormatFloat(float64(f), 'g', -1, 32).
+// See FromFloat64Shortest.
+//
+func FromFloat32Shortest(f float32) (Quad, error) {
+
+	if f != f || math.IsInf(float64(f), 0) {
+		return fromFloatSpecial(float64(f))
+	}
+
+	mant, exp2, neg := float32Parts(f)
+
+	return fromFloatShortest(mant, exp2, neg, float32Format), nil
+}
+
+// fromFloatShortest implements FromFloat64Shortest and FromFloat32Shortest, for the number (-1)^neg * mant * 2^exp2 of the format f.
+//
+func fromFloatShortest(mant uint64, exp2 int32, neg bool, f binaryFormat) Quad {
+	var buff [DecquadPmax]byte
+
+	if mant == 0 {
+		return fromUint128(0, 0, 0, neg)
+	}
+
+	digits, k := f.shortestDigits(buff[:0], mant, exp2)
+
+	exp := k - int32(len(digits))
+
+	if exp > 0 && int32(len(digits))+exp <= DecquadPmax { // e.g. 1E+2 is written 100
+		for ; exp > 0; exp-- {
+			digits = append(digits, 0)
+		}
+	}
+
+	hi, lo := digitsToUint128(digits)
+
+	return fromUint128(hi, lo, exp, neg)
+}
+
+// fromFloatSpecial returns NaN or Infinity for a NaN or Inf float, with QuadError(InvalidOperation) as error.
+//
+func fromFloatSpecial(f float64) (Quad, error) {
+
+	switch {
+	case math.IsNaN(f):
+		return NaN(), QuadError(InvalidOperation)
+	case f < 0:
+		return g_infinity.Neg(), QuadError(InvalidOperation)
+	default:
+		return g_infinity, QuadError(InvalidOperation)
+	}
+}
+
+// fromUint128 returns the Quad (-1)^neg * (hi * 2^64 + lo) * 10^exp, rounded to 34 digits with RoundHalfEven mode, and Inexact is set if needed.
+// The coefficient is passed by value to C, so that no buffer of the caller escapes to the heap.
+//
+func fromUint128(hi uint64, lo uint64, exp int32, neg bool) Quad {
+	var sign C.uint32_t
+
+	if neg {
+		sign = 1
+	}
+
+	return Quad(C.mdq_from_uint128(C.uint64_t(hi), C.uint64_t(lo), C.int32_t(exp), sign, C.int(RoundHalfEven)))
+}
+
+// digitsToUint128 returns the integer whose decimal digits are digits, most significant first, as hi * 2^64 + lo.
+// digits must have at most 38 digits, so that the integer fits in 128 bits.
+//
+func digitsToUint128(digits []byte) (hi uint64, lo uint64) {
+
+	for _, d := range digits {
+		h, l := bits.Mul64(lo, 10)
+		l, carry := bits.Add64(l, uint64(d), 0)
+		hi, lo = hi*10+h+carry, l
+	}
+
+	return hi, lo
+}
+
+/************************************************************************/
+/*                                                                      */
+/*            decimal digits of binary numbers, internal helpers        */
+/*                                                                      */
+/************************************************************************/
+
+// decimalExponent returns an estimate of the decimal exponent k of mant * 2^exp2, such that 10^(k-1) <= mant * 2^exp2 < 10^k.
+// The estimate is never too large, but it can be 1 too small.
+//
+func decimalExponent(mant uint64, exp2 int32) int32 {
+
+	log2 := exp2 + int32(bits.Len64(mant)) - 1 // 2^log2 <= mant * 2^exp2
+
+	return int32(math.Ceil(float64(log2)*log10Of2 - 1e-10))
+}
+
+// exactDigits appends to dst the decimal digits of mant * 2^exp2, with mant > 0, and returns them with k such that the value is 0.d1d2d3... * 10^k.
+//
+// The digits are generated until the value is exact, and at least up to the units digit, so that an integer has exponent 0.
+// If there are more than DecquadPmax+1 significant digits, they are stopped there, and a last sticky digit 1 is appended if the discarded digits are not all 0.
+// The rounding of the result to DecquadPmax digits is then the same as the rounding of the exact value.
+//
+func exactDigits(dst []byte, mant uint64, exp2 int32) ([]byte, int32) {
+	var r, s nat
+
+	// mant * 2^exp2 = r / s * 10^k
+
+	k := decimalExponent(mant, exp2)
+
+	r.setUint64(1)
+	if k < 0 {
+		r.mulPow10(-k)
+	}
+	r.mulWord(mant)
+
+	s.setUint64(1)
+	if k > 0 {
+		s.mulPow10(k)
+	}
+
+	if exp2 > 0 {
+		r.shl(uint(exp2))
+	} else {
+		s.shl(uint(-exp2))
+	}
+
+	for r.cmp(&s) >= 0 { // the estimate of k was too small
+		s.mulWord(10)
+		k++
+	}
+
+	normalize(&s, &r, nil, nil)
+
+	for n := int32(1); ; n++ {
+		r.mulWord(10)
+		dst = append(dst, r.divDigit(&s))
+
+		if r.n == 0 && n >= k {
+			return dst, k
+		}
+
+		if n == DecquadPmax+1 {
+			if r.n != 0 {
+				dst = append(dst, 1)
+			}
+			return dst, k
+		}
+	}
+}
+
+// shortestDigits appends to dst the fewest decimal digits that convert back to mant * 2^exp2 in the format f, with mant > 0.
+// It returns them with k such that the value is 0.d1d2d3... * 10^k.
+// If several digit strings are as short, the one nearest to the exact value is chosen, ties to even, as with strconv.FormatFloat.
+//
+// It is the free-format algorithm of Steele & White, as described by Burger & Dybvig in "Printing Floating-Point Numbers Quickly and Accurately".
+// A digit string converts back to the number if it is between the midpoints with the neighbours, included if mant is even, as parsing rounds ties to even.
+//
+func (f binaryFormat) shortestDigits(dst []byte, mant uint64, exp2 int32) ([]byte, int32) {
+	var r, s, mPlus, mMinus, p, t nat
+
+	even := mant%2 == 0
+
+	// mant * 2^exp2 = r / s * 10^k, and the midpoints with the neighbours are (r - mMinus) / s * 10^k and (r + mPlus) / s * 10^k.
+	// All the values are doubled, so that the midpoints are integers, and doubled again if the gap below is half the gap above, at the start of a binade.
+
+	shift := uint(1)
+	if mant == 1<<uint(f.prec-1) && exp2 > f.emin {
+		shift = 2
+	}
+
+	k := decimalExponent(mant, exp2)
+
+	p.setUint64(1)
+	if k < 0 {
+		p.mulPow10(-k)
+	}
+
+	r, mPlus, mMinus = p, p, p
+	r.mulWord(mant)
+
+	s.setUint64(1)
+	if k > 0 {
+		s.mulPow10(k)
+	}
+
+	if exp2 > 0 {
+		r.shl(uint(exp2) + shift)
+		mPlus.shl(uint(exp2) + shift - 1)
+		mMinus.shl(uint(exp2))
+		s.shl(shift)
+	} else {
+		r.shl(shift)
+		mPlus.shl(shift - 1)
+		s.shl(uint(-exp2) + shift)
+	}
+
+	for { // the estimate of k was too small if the upper midpoint is 10^k or more
+		t = r
+		cmp := t.add(&mPlus).cmp(&s)
+		if cmp < 0 || cmp == 0 && !even {
+			break
+		}
+		s.mulWord(10)
+		k++
+	}
+
+	normalize(&s, &r, &mPlus, &mMinus)
+
+	for {
+		r.mulWord(10)
+		mPlus.mulWord(10)
+		mMinus.mulWord(10)
+		d := r.divDigit(&s)
+
+		cmp := r.cmp(&mMinus)
+		low := cmp < 0 || cmp == 0 && even // d is within the lower midpoint
+
+		t = r
+		cmp = t.add(&mPlus).cmp(&s)
+		high := cmp > 0 || cmp == 0 && even // d+1 is within the upper midpoint
+
+		switch {
+		case !low && !high:
+			dst = append(dst, d)
+			continue
+		case high && !low:
+			d++
+		case high && low: // the nearest of d and d+1, ties to even
+			t = r
+			if cmp := t.shl(1).cmp(&s); cmp > 0 || cmp == 0 && d%2 == 1 {
+				d++
+			}
+		}
+
+		return append(dst, d), k
+	}
+}
+
+/************************************************************************/
+/*                                                                      */
+/*               fixed size natural numbers, internal helpers           */
+/*                                                                      */
+/************************************************************************/
+
+// natWords is the size of a nat, 1280 bits.
+// The largest numbers are used by shortestDigits and exactDigits for the smallest float64 subnormals, 2^53 * 10^324 shifted by normalize, less than 1200 bits.
+//
+const natWords = 20
+
+// nat is a natural number of fixed size, so that the conversions between Quad and floats don't allocate memory.
+// It is a value type: an assignment copies the number.
+//
+type nat struct {
+	w [natWords]uint64 // least significant word first
+	n int              // number of significant words. The words above are 0.
+}
+
+// pow5 contains the powers of 5 which fit in an uint64, from 5^0 to 5^27.
+//
+var pow5 = func() (table [28]uint64) {
+
+	table[0] = 1
+	for i := 1; i < len(table); i++ {
+		table[i] = table[i-1] * 5
+	}
+
+	return table
+}()
+
+// setUint64 sets z to v, and returns z.
+//
+func (z *nat) setUint64(v uint64) *nat {
+
+	return z.setUint128(0, v)
+}
+
+// setUint128 sets z to the 128 bits integer h:l, and returns z.
+//
+func (z *nat) setUint128(h uint64, l uint64) *nat {
+
+	*z = nat{}
+	z.w[0], z.w[1], z.n = l, h, 2
+	z.norm()
+
+	return z
+}
+
+// norm removes the most significant zero words from z.n.
+//
+func (z *nat) norm() {
+
+	for z.n > 0 && z.w[z.n-1] == 0 {
+		z.n--
+	}
+}
+
+// bitLen returns the number of bits of z, without leading zeros.
+//
+func (z *nat) bitLen() int {
+
+	if z.n == 0 {
+		return 0
+	}
+
+	return 64*(z.n-1) + bits.Len64(z.w[z.n-1])
+}
+
+// cmp returns -1, 0 or +1 if z is less than, equal to, or greater than x.
+//
+func (z *nat) cmp(x *nat) int {
+
+	switch {
+	case z.n < x.n:
+		return -1
+	case z.n > x.n:
+		return 1
+	}
+
+	for i := z.n - 1; i >= 0; i-- {
+		switch {
+		case z.w[i] < x.w[i]:
+			return -1
+		case z.w[i] > x.w[i]:
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// add sets z to z + x, and returns z.
+//
+func (z *nat) add(x *nat) *nat {
+	var carry uint64
+
+	n := z.n
+	if x.n > n {
+		n = x.n
+	}
+
+	for i := 0; i < n; i++ {
+		z.w[i], carry = bits.Add64(z.w[i], x.w[i], carry)
+	}
+
+	if carry != 0 {
+		z.w[n] = carry
+		n++
+	}
+
+	z.n = n
+
+	return z
+}
+
+// sub sets z to z - x, and returns z. x must not be greater than z.
+//
+func (z *nat) sub(x *nat) *nat {
+	var borrow uint64
+
+	for i := 0; i < z.n; i++ {
+		z.w[i], borrow = bits.Sub64(z.w[i], x.w[i], borrow)
+	}
+
+	z.norm()
+
+	return z
+}
+
+// mulWord sets z to z * m, and returns z.
+//
+func (z *nat) mulWord(m uint64) *nat {
+	var carry uint64
+
+	for i := 0; i < z.n; i++ {
+		hi, lo := bits.Mul64(z.w[i], m)
+		lo, c := bits.Add64(lo, carry, 0)
+		z.w[i], carry = lo, hi+c
+	}
+
+	if carry != 0 {
+		z.w[z.n] = carry
+		z.n++
+	}
+
+	z.norm()
+
+	return z
+}
+
+// mulPow5 sets z to z * 5^k, with k >= 0, and returns z.
+//
+func (z *nat) mulPow5(k int32) *nat {
+
+	for ; k >= 27; k -= 27 {
+		z.mulWord(pow5[27])
+	}
+
+	return z.mulWord(pow5[k])
+}
+
+// mulPow10 sets z to z * 10^k, with k >= 0, and returns z.
+//
+func (z *nat) mulPow10(k int32) *nat {
+
+	return z.mulPow5(k).shl(uint(k))
+}
+
+// shl sets z to z * 2^s, and returns z.
+//
+func (z *nat) shl(s uint) *nat {
+
+	if z.n == 0 {
+		return z
+	}
+
+	words, b := int(s/64), s%64
+
+	for i := z.n; i >= 0; i-- { // the word z.n is 0, and receives the high bits of the word below
+		var v uint64
+		if i < z.n {
+			v = z.w[i] << b
+		}
+		if i > 0 && b != 0 {
+			v |= z.w[i-1] >> (64 - b)
+		}
+		if v != 0 || i < z.n {
+			z.w[i+words] = v
+		}
+	}
+
+	for i := 0; i < words; i++ {
+		z.w[i] = 0
+	}
+
+	z.n += words + 1
+	z.norm()
+
+	return z
+}
+
+// normalize shifts s to the left so that its most significant bit is the bit 63 of a word, as required by divDigit.
+// r, and x and y if not nil, are shifted by the same amount, so that their ratios with s don't change.
+//
+func normalize(s *nat, r *nat, x *nat, y *nat) {
+
+	shift := uint(64*s.n - s.bitLen())
+
+	s.shl(shift)
+	r.shl(shift)
+
+	if x != nil {
+		x.shl(shift)
+	}
+
+	if y != nil {
+		y.shl(shift)
+	}
+}
+
+// divDigit sets z to z mod s, and returns the quotient z / s, which must be less than 10.
+// s must be normalized: its most significant bit is the bit 63 of a word.
+//
+// The quotient is estimated from the 2 most significant words of z and the most significant word of s. With s normalized, the estimate is at most 2 too large (Knuth, TAOCP vol. 2, 4.3.1).
+//
+func (z *nat) divDigit(s *nat) byte {
+	var carry, borrow uint64
+
+	n := s.n
+	if z.n < n {
+		return 0 // z < s
+	}
+
+	q, _ := bits.Div64(z.w[n], z.w[n-1], s.w[n-1]) // z.w[n] is less than 10, and s.w[n-1] is at least 2^63, so the quotient fits in an uint64
+	if q > 9 {
+		q = 9
+	}
+
+	for i := 0; i < n; i++ { // z -= q * s
+		hi, lo := bits.Mul64(s.w[i], q)
+		lo, c := bits.Add64(lo, carry, 0)
+		carry = hi + c
+		z.w[i], borrow = bits.Sub64(z.w[i], lo, borrow)
+	}
+	z.w[n], borrow = bits.Sub64(z.w[n], carry, borrow)
+
+	for borrow != 0 { // q was too large, and z is negative in two's complement: add s back until the addition carries out
+		var c uint64
+		for i := 0; i < n; i++ {
+			z.w[i], c = bits.Add64(z.w[i], s.w[i], c)
+		}
+		z.w[n], c = bits.Add64(z.w[n], 0, c)
+		borrow ^= c
+		q--
+	}
+
+	z.n = n + 1
+	z.norm()
+
+	return byte(q)
+}
@@ -0,0 +1,314 @@
+package decnum
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestToFloat64(t *testing.T) {
+
+	samples := []struct {
+		a        string
+		expected float64
+		err      error
+	}{
+		{"0.1", 0.1, nil},
+		{"-12345.25", -12345.25, nil},
+		{"1E+23", 1e23, nil},
+		{"9007199254740993", 9007199254740992, nil},                                           // halfway, to even
+		{"9007199254740995", 9007199254740996, nil},                                           // halfway, to even
+		{"9007199254740993.000000000000000001", 9007199254740994, nil},                        // just above halfway
+		{"2.470328229206232720882843964341106E-324", 0, nil},                                  // just below half of the smallest subnormal
+		{"2.470328229206232720882843964341107E-324", 5e-324, nil},                             // just above
+		{"1.797693134862315708145274237317043E+308", math.MaxFloat64, nil},                    // just below the overflow threshold
+		{"1.797693134862315808145274237317043E+308", math.NaN(), QuadError(InvalidOperation)}, // above
+		{"1E-400", 0, nil},
+		{"-1E-400", math.Copysign(0, -1), nil},
+		{"1.23E+2000", math.NaN(), QuadError(InvalidOperation)},
+		{"-Inf", math.Inf(-1), nil},
+		{"0E-20", 0, nil},
+	}
+
+	for _, s := range samples {
+		r, err := must_quad(s.a).ToFloat64()
+		if math.Float64bits(r) != math.Float64bits(s.expected) && !(math.IsNaN(r) && math.IsNaN(s.expected)) || err != s.err {
+			t.Fatalf("%s.ToFloat64() = %g, %v, expected %g, %v", s.a, r, err, s.expected, s.err)
+		}
+	}
+}
+
+// randomQuadString returns a random decimal string with up to 34 digits, around the range of float64 and float32 if small is true.
+//
+func randomQuadString(rnd *rand.Rand, small bool) string {
+
+	digits := make([]byte, 1+rnd.Intn(34))
+	for i := range digits {
+		digits[i] = byte('0' + rnd.Intn(10))
+	}
+
+	exp := rnd.Intn(700) - 360
+	if small {
+		exp = rnd.Intn(100) - 70
+	}
+
+	// halfway cases: the digits of an exact midpoint, possibly changed in the last digit
+
+	if rnd.Intn(4) == 0 {
+		f := math.Float64frombits(rnd.Uint64() &^ (1 << 63))
+		if small {
+			f = float64(math.Float32frombits(rnd.Uint32() &^ (1 << 31)))
+		}
+		if !math.IsInf(f, 0) && !math.IsNaN(f) {
+			next := math.Nextafter(f, math.Inf(1))
+			if small {
+				next = float64(math.Nextafter32(float32(f), float32(math.Inf(1))))
+			}
+			mid := new(big.Float).SetPrec(2000).Add(big.NewFloat(f), big.NewFloat(next))
+			mid.Quo(mid, big.NewFloat(2))
+			s := mid.Text('e', 33)
+			if rnd.Intn(2) == 0 {
+				s = mid.Text('e', rnd.Intn(40))
+			}
+			return s
+		}
+	}
+
+	return string(digits) + "E" + strconv.Itoa(exp)
+}
+
+func TestToFloat64Random(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		s := randomQuadString(rnd, false)
+		a := must_quad(s)
+
+		expected, perr := strconv.ParseFloat(a.String(), 64)
+		r, err := a.ToFloat64()
+
+		if perr != nil { // overflow
+			if err != QuadError(InvalidOperation) {
+				t.Fatalf("%s.ToFloat64() = %g, %v, expected overflow", a, r, err)
+			}
+			continue
+		}
+
+		if r != expected || err != nil {
+			t.Fatalf("%s.ToFloat64() = %g, %v, expected %g", a, r, err, expected)
+		}
+	}
+}
+
+func TestToFloat32(t *testing.T) {
+
+	samples := []struct {
+		a        string
+		expected float32
+		err      error
+	}{
+		{"0.1", 0.1, nil},
+		{"16777217", 16777216, nil}, // halfway, to even
+		{"16777219", 16777220, nil},
+		{"3.4028235E+38", math.MaxFloat32, nil},
+		{"3.5E+38", float32(math.NaN()), QuadError(InvalidOperation)},
+		{"1E-50", 0, nil},
+		{"Inf", float32(math.Inf(1)), nil},
+	}
+
+	for _, s := range samples {
+		r, err := must_quad(s.a).ToFloat32()
+		if math.Float32bits(r) != math.Float32bits(s.expected) && !(r != r && s.expected != s.expected) || err != s.err {
+			t.Fatalf("%s.ToFloat32() = %g, %v, expected %g, %v", s.a, r, err, s.expected, s.err)
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(2))
+
+	for i := 0; i < 20000; i++ {
+		a := must_quad(randomQuadString(rnd, true))
+
+		expected, perr := strconv.ParseFloat(a.String(), 32)
+		r, err := a.ToFloat32()
+
+		if perr != nil {
+			if err != QuadError(InvalidOperation) {
+				t.Fatalf("%s.ToFloat32() = %g, %v, expected overflow", a, r, err)
+			}
+			continue
+		}
+
+		if r != float32(expected) || err != nil {
+			t.Fatalf("%s.ToFloat32() = %g, %v, expected %g", a, r, err, expected)
+		}
+	}
+}
+
+func TestFromFloat64Exact(t *testing.T) {
+
+	samples := []struct {
+		f        float64
+		expected string
+		status   Status
+	}{
+		{0.1, "0.1000000000000000055511151231257827", Inexact},
+		{0.375, "0.375", 0},
+		{-1024, "-1024", 0},
+		{1e23, "99999999999999991611392", 0},
+		{0, "0", 0},
+		{math.MaxFloat64, "1.797693134862315708145274237317044E+308", Inexact},
+		{5e-324, "4.940656458412465441765687928682214E-324", Inexact},
+		{math.Ldexp(1, -1074+20), "5.180653786536309363064897985505881E-318", Inexact},
+	}
+
+	for _, s := range samples {
+		r, err := FromFloat64Exact(s.f)
+		if r.String() != s.expected || r.Status() != s.status || err != nil {
+			t.Fatalf("FromFloat64Exact(%g) = %s, status %s, %v, expected %s, %s", s.f, r, r.Status(), err, s.expected, s.status)
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(3))
+
+	for i := 0; i < 2000; i++ {
+		f := math.Float64frombits(rnd.Uint64())
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+
+		expected := FromBigFloat(big.NewFloat(f))
+		r, _ := FromFloat64Exact(f)
+
+		if !r.Equal(expected) || r.Status() != expected.Status() {
+			t.Fatalf("FromFloat64Exact(%g) = %s, expected %s", f, r, expected)
+		}
+
+		if r32, _ := FromFloat32Exact(float32(f)); !r32.Equal(FromBigFloat(big.NewFloat(float64(float32(f))))) {
+			t.Fatalf("FromFloat32Exact(%g) = %s", float32(f), r32)
+		}
+	}
+}
+
+func TestFromFloat64Shortest(t *testing.T) {
+
+	samples := []struct {
+		f        float64
+		expected string
+	}{
+		{0.1, "0.1"},
+		{-0.000012345, "-0.000012345"},
+		{100, "100"},
+		{1e33, "1000000000000000000000000000000000"},
+		{1e34, "1E+34"},
+		{1e100, "1E+100"},
+		{123456789e300, "1.23456789E+308"},
+		{5e-324, "5E-324"},
+		{math.MaxFloat64, "1.7976931348623157E+308"},
+		{math.SmallestNonzeroFloat64 * (1 << 52), "2.2250738585072014E-308"}, // smallest normal, where the gap below is not half the gap above
+		{1 << 60, "1152921504606847000"},                                     // start of a binade, where the gap below is half the gap above
+		{0, "0"},
+	}
+
+	for _, s := range samples {
+		r, err := FromFloat64Shortest(s.f)
+		if r.String() != s.expected || r.Status() != 0 || err != nil {
+			t.Fatalf("FromFloat64Shortest(%g) = %s, status %s, %v, expected %s", s.f, r, r.Status(), err, s.expected)
+		}
+	}
+
+	if r, _ := FromFloat32Shortest(0.1); r.String() != "0.1" {
+		t.Fatalf("FromFloat32Shortest(0.1) = %s", r)
+	}
+
+	rnd := rand.New(rand.NewSource(4))
+
+	for i := 0; i < 20000; i++ {
+		f := math.Float64frombits(rnd.Uint64())
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			continue
+		}
+
+		r, _ := FromFloat64Shortest(f)
+		if back, _ := r.ToFloat64(); back != f {
+			t.Fatalf("FromFloat64Shortest(%g) = %s, converted back to %g", f, r, back)
+		}
+
+		if expected := must_quad(strconv.FormatFloat(f, 'e', -1, 64)); !r.Equal(expected) {
+			t.Fatalf("FromFloat64Shortest(%g) = %s, expected %s", f, r, expected)
+		}
+
+		f32 := math.Float32frombits(rnd.Uint32())
+		if f32 != f32 || math.IsInf(float64(f32), 0) {
+			continue
+		}
+
+		r, _ = FromFloat32Shortest(f32)
+		if back, _ := r.ToFloat32(); back != f32 {
+			t.Fatalf("FromFloat32Shortest(%g) = %s, converted back to %g", f32, r, back)
+		}
+
+		if expected := strconv.FormatFloat(float64(f32), 'e', -1, 32); !r.Equal(must_quad(expected)) {
+			t.Fatalf("FromFloat32Shortest(%g) = %s, expected %s", f32, r, expected)
+		}
+	}
+}
+
+func TestFromFloatSpecial(t *testing.T) {
+
+	samples := []struct {
+		f        float64
+		expected string
+	}{
+		{math.NaN(), "NaN"},
+		{math.Inf(1), "Infinity"},
+		{math.Inf(-1), "-Infinity"},
+	}
+
+	for _, s := range samples {
+		for _, fn := range []func(float64) (Quad, error){FromFloat64Exact, FromFloat64Shortest} {
+			if r, err := fn(s.f); r.String() != s.expected || err != QuadError(InvalidOperation) {
+				t.Fatalf("conversion of %g = %s, %v, expected %s and error", s.f, r, err, s.expected)
+			}
+		}
+
+		if r := FromFloat(s.f); r.String() != s.expected {
+			t.Fatalf("FromFloat(%g) = %s, expected %s", s.f, r, s.expected)
+		}
+	}
+}
+
+func TestFloatAllocs(t *testing.T) {
+
+	a := must_quad("1.234567890123456789E-310")
+
+	n := testing.AllocsPerRun(100, func() {
+		a.ToFloat64()
+		a.ToFloat32()
+		FromFloat64Exact(0.1)
+		FromFloat64Shortest(0.1)
+		FromFloat32Shortest(0.1)
+	})
+
+	if n != 0 {
+		t.Fatalf("float conversions allocate %v times, expected 0", n)
+	}
+}
+
+func BenchmarkToFloat64(b *testing.B) {
+
+	a := must_quad("12345.6789")
+
+	for i := 0; i < b.N; i++ {
+		a.ToFloat64()
+	}
+}
+
+func BenchmarkFromFloat64Shortest(b *testing.B) {
+
+	for i := 0; i < b.N; i++ {
+		FromFloat64Shortest(12345.6789)
+	}
+}
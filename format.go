@@ -0,0 +1,476 @@
+package decnum
+
+import (
+	"fmt"
+	"strconv"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                            fmt.Formatter                             */
+/*                                                                      */
+/************************************************************************/
+
+// roundToExp returns a rounded so that its exponent is exp, if a has more digits than that. Else, a is returned unchanged.
+// Rounding is exact, as it uses Quantize.
+//
+func (a Quad) roundToExp(exp int32, rounding RoundingMode) Quad {
+
+	if !a.IsFinite() || a.GetExponent() >= exp {
+		return a
+	}
+
+	return a.Quantize(unitQuad(exp), rounding)
+}
+
+// appendDigits appends the coefficient of a to dst, as ASCII digits without leading zeros. Zero is written as "0".
+// It returns the extended buffer, the exponent, and true if a is negative and not zero.
+//
+// a must be finite.
+//
+func (a Quad) appendDigits(dst []byte) (digits []byte, exp int32, neg bool) {
+
+	bcd, exp, neg, _ := a.toBCD()
+
+	i := 0
+	for i < DecquadPmax-1 && bcd[i] == 0 {
+		i++
+	}
+
+	for _, d := range bcd[i:] {
+		dst = append(dst, '0'+d)
+	}
+
+	return dst, exp, neg
+}
+
+// Format implements the fmt.Formatter interface, so that Quad can be printed with fmt.Printf and friends.
+//
+// Supported verbs are:
+//
+//        %v %s      same as String()
+//        %f %F      plain notation, without exponent. Precision is the number of fractional digits. Without precision, all digits are written.
+//        %e %E      scientific notation, e.g. 1.2345e+03. Precision is the number of digits after the point. Without precision, all digits of the coefficient are written.
+//        %g %G      %e for large exponents, %f otherwise, as for float64. Precision is the number of significant digits.
+//
+// Width and the flags '+', ' ', '-' and '0' are supported. The flag '#' keeps trailing zeros with %g.
+//
+// Rounding is done exactly in decimal, with RoundHalfEven mode, like Round().
+// NaN and Infinity are written as by String().
+//
+func (a Quad) Format(f fmt.State, verb rune) {
+	var (
+		buff [64]byte
+		body []byte // number without sign
+		neg  bool
+	)
+
+	prec, hasPrec := f.Precision()
+
+	switch verb {
+	case 'v', 's', 'f', 'F', 'e', 'E', 'g', 'G':
+		if verb == 'v' || verb == 's' || !a.IsFinite() { // NaN and Infinity are written as by String()
+			body = AppendQuad(buff[:0], a)
+			if len(body) > 0 && body[0] == '-' {
+				body, neg = body[1:], true
+			}
+			break
+		}
+
+		switch verb {
+		case 'f', 'F':
+			body, neg = a.appendFixed(buff[:0], prec, hasPrec)
+		case 'e', 'E':
+			body, neg = a.appendExp(buff[:0], prec, hasPrec, byte(verb))
+		default:
+			body, neg = a.appendGeneral(buff[:0], prec, hasPrec, f.Flag('#'), byte(verb-'g'+'e'))
+		}
+
+	default:
+		fmt.Fprintf(f, "%%!%c(decnum.Quad=%s)", verb, a.String())
+		return
+	}
+
+	writePadded(f, body, neg, a.IsFinite())
+}
+
+// writePadded writes the sign and body to f, padded to the width of f.
+// If zeroPad is true and the '0' flag is set, zeros are inserted between sign and body.
+//
+func writePadded(f fmt.State, body []byte, neg bool, zeroPad bool) {
+	var (
+		sign  []byte
+		space = []byte{' '}
+		zero  = []byte{'0'}
+	)
+
+	switch {
+	case neg:
+		sign = []byte{'-'}
+	case f.Flag('+'):
+		sign = []byte{'+'}
+	case f.Flag(' '):
+		sign = []byte{' '}
+	}
+
+	pad := 0
+	if width, ok := f.Width(); ok && width > len(sign)+len(body) {
+		pad = width - len(sign) - len(body)
+	}
+
+	switch {
+	case f.Flag('-'):
+		f.Write(sign)
+		f.Write(body)
+		for ; pad > 0; pad-- {
+			f.Write(space)
+		}
+	case f.Flag('0') && zeroPad:
+		f.Write(sign)
+		for ; pad > 0; pad-- {
+			f.Write(zero)
+		}
+		f.Write(body)
+	default:
+		for ; pad > 0; pad-- {
+			f.Write(space)
+		}
+		f.Write(sign)
+		f.Write(body)
+	}
+}
+
+// appendFixed appends |a| in plain notation, with prec fractional digits if hasPrec is true, else with all the digits of a.
+// a must be finite.
+//
+func (a Quad) appendFixed(dst []byte, prec int, hasPrec bool) ([]byte, bool) {
+	var buff [DecquadPmax]byte
+
+	if hasPrec {
+		a = a.roundToExp(clampExp(-int64(prec)), RoundHalfEven)
+	}
+
+	digits, exp, neg := a.appendDigits(buff[:0])
+
+	frac := 0
+	if exp < 0 {
+		frac = int(-exp)
+	}
+	if hasPrec {
+		frac = prec
+	}
+
+	return appendPlain(dst, digits, int(exp), frac), neg
+}
+
+// appendPlain appends digits * 10^exp in plain notation, with frac fractional digits. frac must be >= -exp.
+//
+func appendPlain(dst []byte, digits []byte, exp int, frac int) []byte {
+
+	n := len(digits)
+
+	// integral part
+
+	switch {
+	case exp >= 0:
+		dst = append(dst, digits...)
+		if digits[0] != '0' {
+			for i := 0; i < exp; i++ {
+				dst = append(dst, '0')
+			}
+		}
+	case n+exp > 0:
+		dst = append(dst, digits[:n+exp]...)
+	default:
+		dst = append(dst, '0')
+	}
+
+	if frac == 0 {
+		return dst
+	}
+
+	// fractional part
+
+	dst = append(dst, '.')
+
+	written := 0
+	if exp < 0 {
+		for i := n; i < -exp; i++ { // leading zeros, e.g. 0.00123
+			dst = append(dst, '0')
+			written++
+		}
+		start := n + exp
+		if start < 0 {
+			start = 0
+		}
+		dst = append(dst, digits[start:]...)
+		written += n - start
+	}
+
+	for ; written < frac; written++ {
+		dst = append(dst, '0')
+	}
+
+	return dst
+}
+
+// appendExp appends |a| in scientific notation, with prec digits after the point if hasPrec is true, else with all the digits of the coefficient.
+// e is 'e' or 'E'. a must be finite.
+//
+func (a Quad) appendExp(dst []byte, prec int, hasPrec bool, e byte) ([]byte, bool) {
+	var buff [DecquadPmax]byte
+
+	digits, exp, neg := a.appendDigits(buff[:0])
+
+	if hasPrec && len(digits) > prec+1 && digits[0] != '0' {
+		adjusted := int64(exp) + int64(len(digits)) - 1
+		a = a.roundToExp(clampExp(adjusted-int64(prec)), RoundHalfEven)
+		digits, exp, neg = a.appendDigits(buff[:0])
+	}
+
+	frac := len(digits) - 1
+	if hasPrec {
+		frac = prec
+	}
+
+	return appendScientific(dst, digits, int(exp), frac, false, e), neg
+}
+
+// appendScientific appends digits * 10^exp in scientific notation, with frac digits after the point.
+// If trim is true, trailing zeros after the point are removed.
+// Zero is written with exponent 0.
+//
+func appendScientific(dst []byte, digits []byte, exp int, frac int, trim bool, e byte) []byte {
+
+	adjusted := exp + len(digits) - 1
+	if digits[0] == '0' {
+		adjusted = 0
+	}
+
+	mantissa := digits[1:]
+	if len(mantissa) > frac {
+		mantissa = mantissa[:frac] // only zeros are discarded, as digits has been rounded by the caller
+	}
+
+	dst = append(dst, digits[0])
+
+	if trim {
+		for len(mantissa) > 0 && mantissa[len(mantissa)-1] == '0' {
+			mantissa = mantissa[:len(mantissa)-1]
+		}
+		frac = len(mantissa)
+	}
+
+	if frac > 0 {
+		dst = append(dst, '.')
+		dst = append(dst, mantissa...)
+		for i := len(mantissa); i < frac; i++ {
+			dst = append(dst, '0')
+		}
+	}
+
+	dst = append(dst, e)
+	if adjusted < 0 {
+		dst = append(dst, '-')
+		adjusted = -adjusted
+	} else {
+		dst = append(dst, '+')
+	}
+	if adjusted < 10 {
+		dst = append(dst, '0')
+	}
+
+	return strconv.AppendInt(dst, int64(adjusted), 10)
+}
+
+// appendGeneral appends |a| with %g rules, as for float64:
+// scientific notation is used if the exponent is < -4 or >= precision, else plain notation.
+// Trailing zeros are removed if sharp is false and a precision is given.
+//
+// Without precision, all the digits of the coefficient are significant, and are written.
+// e is 'e' or 'E'. a must be finite.
+//
+func (a Quad) appendGeneral(dst []byte, prec int, hasPrec bool, sharp bool, e byte) ([]byte, bool) {
+	var buff [DecquadPmax]byte
+
+	digits, exp, neg := a.appendDigits(buff[:0])
+
+	if digits[0] == '0' { // zero
+		if hasPrec && !sharp {
+			return append(dst, '0'), false
+		}
+		frac := 0
+		if exp < 0 {
+			frac = int(-exp)
+		}
+		return appendPlain(dst, digits, int(exp), frac), false
+	}
+
+	eprec := prec
+	if hasPrec {
+		if eprec == 0 {
+			eprec = 1
+		}
+		if len(digits) > eprec {
+			adjusted := int64(exp) + int64(len(digits)) - 1
+			a = a.roundToExp(clampExp(adjusted-int64(eprec)+1), RoundHalfEven)
+			digits, exp, neg = a.appendDigits(buff[:0])
+		}
+	} else {
+		eprec = 6 // same rule as strconv.FormatFloat(f, 'g', -1, 64)
+	}
+
+	adjusted := int(exp) + len(digits) - 1
+	trim := hasPrec && !sharp
+
+	if adjusted < -4 || adjusted >= eprec {
+		frac := len(digits) - 1
+		if hasPrec {
+			frac = eprec - 1
+		}
+		return appendScientific(dst, digits, int(exp), frac, trim, e), neg
+	}
+
+	frac := 0
+	if exp < 0 {
+		frac = int(-exp)
+	}
+	if hasPrec && eprec-1-adjusted > frac {
+		frac = eprec - 1 - adjusted
+	}
+
+	dst = appendPlain(dst, digits, int(exp), frac)
+
+	if trim && frac > 0 {
+		for dst[len(dst)-1] == '0' {
+			dst = dst[:len(dst)-1]
+		}
+		if dst[len(dst)-1] == '.' {
+			dst = dst[:len(dst)-1]
+		}
+	}
+
+	return dst, neg
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                  explicit plain, scientific, engineering             */
+/*                                                                      */
+/************************************************************************/
+
+// FormatMode is the notation used by AppendFormat.
+//
+type FormatMode int
+
+const (
+	FormatPlain       FormatMode = iota // no exponent, e.g. 1234.5 or 0.0000012
+	FormatScientific                    // one digit before the point, and an exponent, e.g. 1.2345E+3 or 1.2E-6
+	FormatEngineering                   // 1 to 3 digits before the point, and an exponent multiple of 3, e.g. 1.2345E+3 or 1.2E-6
+)
+
+func (mode FormatMode) String() string {
+
+	switch mode {
+	case FormatPlain:
+		return "FormatPlain"
+	case FormatScientific:
+		return "FormatScientific"
+	case FormatEngineering:
+		return "FormatEngineering"
+	default:
+		return "Unknown format mode"
+	}
+}
+
+// AppendFormat appends the string representation of a to dst, always in the notation passed as argument.
+// Unlike String() and QuadToString(), it never switches to another notation depending on the value.
+//
+//        FormatPlain          digits is the number of fractional digits.                    1.5E+4 with 2 digits is "15000.00"
+//        FormatScientific     digits is the number of significant digits.                   15000  with 3 digits is "1.50E+4"
+//        FormatEngineering    digits is the number of significant digits.                   1.5E-7 with 2 digits is "150E-9"
+//
+// The exponent is always written with FormatScientific and FormatEngineering, even if it is 0, e.g. "1.5E+0".
+// Zero is written with exponent 0, e.g. "0.00E+0".
+//
+// If digits is negative, all the digits of the coefficient are written, and no rounding occurs.
+// Else, the number is rounded exactly with RoundHalfEven mode, or padded with zeros.
+// With FormatEngineering, zeros are added if digits is too small to fill the integral part, e.g. 1.5E+4 with 1 digit is "20E+3".
+//
+// NaN and Infinity are written as by String(). An unknown mode writes the number as by String().
+//
+// The status field of a is not checked.
+//
+func (a Quad) AppendFormat(dst []byte, mode FormatMode, digits int) []byte {
+	var (
+		buff [DecquadPmax]byte
+		coef []byte
+		exp  int32
+		neg  bool
+	)
+
+	if !a.IsFinite() || mode < FormatPlain || mode > FormatEngineering {
+		return AppendQuad(dst, a)
+	}
+
+	if mode == FormatPlain {
+		body, neg := a.appendFixed(buff[:0], digits, digits >= 0)
+		if neg {
+			dst = append(dst, '-')
+		}
+		return append(dst, body...)
+	}
+
+	coef, exp, neg = a.appendDigits(buff[:0])
+
+	if digits >= 0 && len(coef) > digits && coef[0] != '0' {
+		if digits == 0 {
+			digits = 1
+		}
+		adjusted := int64(exp) + int64(len(coef)) - 1
+		a = a.roundToExp(clampExp(adjusted-int64(digits)+1), RoundHalfEven)
+		coef, exp, neg = a.appendDigits(buff[:0])
+		if len(coef) > digits { // a carry adds a trailing zero, e.g. 9.99 rounded to 2 digits is 10.0
+			exp += int32(len(coef) - digits)
+			coef = coef[:digits]
+		}
+	}
+
+	adjusted := int(exp) + len(coef) - 1
+	if coef[0] == '0' {
+		adjusted = 0
+	}
+
+	for digits > len(coef) {
+		coef = append(coef, '0')
+	}
+
+	if neg {
+		dst = append(dst, '-')
+	}
+
+	intDigits := 1
+	if mode == FormatEngineering {
+		intDigits = adjusted%3 + 1
+		if intDigits <= 0 {
+			intDigits += 3
+		}
+	}
+
+	for len(coef) < intDigits {
+		coef = append(coef, '0')
+	}
+
+	dst = append(dst, coef[:intDigits]...)
+	if len(coef) > intDigits {
+		dst = append(dst, '.')
+		dst = append(dst, coef[intDigits:]...)
+	}
+
+	dst = append(dst, 'E')
+	if adjusted-intDigits+1 >= 0 {
+		dst = append(dst, '+')
+	}
+
+	return strconv.AppendInt(dst, int64(adjusted-intDigits+1), 10)
+}
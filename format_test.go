@@ -0,0 +1,155 @@
+package decnum
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_format(t *testing.T) {
+
+	var samples = []struct {
+		format   string
+		a        string
+		expected string
+	}{
+		{"%v", "123.4500", "123.4500"},
+		{"%s", "-0.0000001", "-0.0000001"},
+		{"%v", "1E+40", "1E+40"},
+		{"%8v|", "1.5", "     1.5|"},
+		{"%-8v|", "1.5", "1.5     |"},
+		{"%+v", "1.5", "+1.5"},
+
+		{"%f", "123.4500", "123.4500"},
+		{"%f", "1E+40", "10000000000000000000000000000000000000000"},
+		{"%f", "1.23E-7", "0.000000123"},
+		{"%.2f", "123.455", "123.46"},
+		{"%.2f", "123.445", "123.44"}, // RoundHalfEven
+		{"%.2f", "-123.4", "-123.40"},
+		{"%.0f", "2.5", "2"},
+		{"%.0f", "3.5", "4"},
+		{"%.2f", "-0.001", "0.00"},
+		{"%.3f", "1E+5", "100000.000"},
+		{"%.1f", "9.96", "10.0"},
+		{"%.40f", "0.1", "0.1000000000000000000000000000000000000000"},
+		{"%.2f", "1234567890123456789012345678901234", "1234567890123456789012345678901234.00"},
+		{"%10.2f|", "3.14159", "      3.14|"},
+		{"%-10.2f|", "3.14159", "3.14      |"},
+		{"%010.2f", "-3.14159", "-000003.14"},
+		{"%+.2f", "3.14159", "+3.14"},
+		{"% .2f", "3.14159", " 3.14"},
+		{"%08.2f", "Inf", "Infinity"},
+		{"%010.2f", "-Inf", " -Infinity"},
+		{"%.2f", "NaN", "NaN"},
+
+		{"%e", "123.4500", "1.234500e+02"},
+		{"%E", "123.4500", "1.234500E+02"},
+		{"%e", "1", "1e+00"},
+		{"%.3e", "123456", "1.235e+05"},
+		{"%.3e", "0.000123456", "1.235e-04"},
+		{"%.1e", "9.96", "1.0e+01"},
+		{"%.5e", "1.5", "1.50000e+00"},
+		{"%.2e", "0.000", "0.00e+00"},
+		{"%e", "1.23E+400", "1.23e+400"},
+		{"%12.2e|", "-12345", "   -1.23e+04|"},
+
+		{"%g", "123.4500", "123.4500"},
+		{"%g", "1234567", "1.234567e+06"},
+		{"%g", "0.00001", "1e-05"},
+		{"%g", "0.0001", "0.0001"},
+		{"%g", "0.00", "0.00"},
+		{"%.3g", "123.4500", "123"},
+		{"%.3g", "1234.5", "1.23e+03"},
+		{"%.3G", "1234.5", "1.23E+03"},
+		{"%.10g", "123.4500", "123.45"},
+		{"%#.10g", "123.4500", "123.4500000"},
+		{"%.3g", "0.000012345", "1.23e-05"},
+		{"%.2g", "0.000", "0"},
+
+		{"%d", "12", "%!d(decnum.Quad=12)"},
+	}
+
+	for _, s := range samples {
+		r := fmt.Sprintf(s.format, must_quad(s.a))
+		if r != s.expected {
+			t.Fatalf("Sprintf(%q, %s) = %q, expected %q", s.format, s.a, r, s.expected)
+		}
+	}
+}
+
+func TestToEngString(t *testing.T) {
+
+	samples := []struct {
+		a        string
+		expected string
+	}{
+		{"123", "123"},
+		{"1.5E+4", "15E+3"},
+		{"1.5E-7", "150E-9"},
+		{"-1.23E+10", "-12.3E+9"},
+		{"0.000001", "0.000001"},
+		{"1E+3", "1E+3"},
+		{"0E+2", "0.0E+3"},
+		{"-0", "0"},
+		{"NaN", "NaN"},
+		{"-Inf", "-Infinity"},
+	}
+
+	for _, s := range samples {
+		r := must_quad(s.a).ToEngString()
+		if r != s.expected {
+			t.Fatalf("%s.ToEngString() = %q, expected %q", s.a, r, s.expected)
+		}
+	}
+}
+
+func TestAppendFormat(t *testing.T) {
+
+	samples := []struct {
+		a        string
+		mode     FormatMode
+		digits   int
+		expected string
+	}{
+		{"1.5E+4", FormatPlain, -1, "15000"},
+		{"1.5E+4", FormatPlain, 2, "15000.00"},
+		{"1.5E-7", FormatPlain, -1, "0.00000015"},
+		{"-1.235", FormatPlain, 2, "-1.24"},
+		{"1.225", FormatPlain, 2, "1.22"},
+		{"1.5E+40", FormatPlain, 0, "15000000000000000000000000000000000000000"},
+
+		{"15000", FormatScientific, -1, "1.5000E+4"},
+		{"15000", FormatScientific, 3, "1.50E+4"},
+		{"1.5", FormatScientific, -1, "1.5E+0"},
+		{"0.000123456", FormatScientific, 3, "1.23E-4"},
+		{"9.99", FormatScientific, 2, "1.0E+1"},
+		{"9.99", FormatScientific, 0, "1E+1"},
+		{"-7", FormatScientific, 4, "-7.000E+0"},
+		{"0.00", FormatScientific, 3, "0.00E+0"},
+		{"0", FormatScientific, -1, "0E+0"},
+
+		{"1.5E-7", FormatEngineering, -1, "150E-9"},
+		{"1.5E-7", FormatEngineering, 2, "150E-9"},
+		{"1.5E+4", FormatEngineering, 1, "20E+3"},
+		{"123456", FormatEngineering, -1, "123.456E+3"},
+		{"123456", FormatEngineering, 4, "123.5E+3"},
+		{"12", FormatEngineering, -1, "12E+0"},
+		{"999.9", FormatEngineering, 3, "1.00E+3"},
+		{"-0.0012", FormatEngineering, -1, "-1.2E-3"},
+		{"0", FormatEngineering, 2, "0.0E+0"},
+
+		{"NaN", FormatScientific, 2, "NaN"},
+		{"-Inf", FormatPlain, 2, "-Infinity"},
+	}
+
+	for _, s := range samples {
+		r := string(must_quad(s.a).AppendFormat(nil, s.mode, s.digits))
+		if r != s.expected {
+			t.Fatalf("%s.AppendFormat(%s, %d) = %q, expected %q", s.a, s.mode, s.digits, r, s.expected)
+		}
+	}
+
+	r := string(must_quad("1.5").AppendFormat([]byte("x="), FormatScientific, 2))
+	if r != "x=1.5E+0" {
+		t.Fatalf("AppendFormat must append to dst, got %q", r)
+	}
+}
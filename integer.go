@@ -0,0 +1,172 @@
+package decnum
+
+import (
+	"math"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                     conversion to and from integers                  */
+/*                                                                      */
+/************************************************************************/
+
+// Integer is the set of the Go integer types, accepted by FromInteger.
+//
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// FromInteger returns a Quad from an integer value of any type.
+//
+// No error occurs, as any Go integer has at most 20 digits.
+//
+func FromInteger[T Integer](value T) Quad {
+
+	u := uint64(value)
+	neg := value < 0
+	if neg {
+		u = -u // also correct for math.MinInt64
+	}
+
+	return fromUint128(0, u, 0, neg)
+}
+
+// magnitude rounds a to an integer with the rounding mode passed as argument, and returns its absolute value and its sign.
+// neg is false for zero.
+//
+// The error is QuadError(InvalidOperation) if a is NaN or Infinity, and QuadError(Overflow) if the absolute value doesn't fit in an uint64.
+//
+func (a Quad) magnitude(rounding RoundingMode) (u uint64, neg bool, err error) {
+
+	if !a.IsFinite() {
+		return 0, false, QuadError(InvalidOperation)
+	}
+
+	bcd, exp, neg, _ := a.ClearStatus().ToIntegral(rounding).toBCD() // exponent of the result is >= 0
+
+	for _, d := range bcd {
+		if u > (math.MaxUint64-uint64(d))/10 {
+			return 0, false, QuadError(Overflow)
+		}
+		u = u*10 + uint64(d)
+	}
+
+	for ; exp > 0 && u != 0; exp-- {
+		if u > math.MaxUint64/10 {
+			return 0, false, QuadError(Overflow)
+		}
+		u *= 10
+	}
+
+	return u, neg, nil
+}
+
+// toSigned returns a rounded to an integer, if it is in the range [min, max].
+//
+func (a Quad) toSigned(rounding RoundingMode, min int64, max int64) (int64, error) {
+
+	u, neg, err := a.magnitude(rounding)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case neg && u > uint64(-(min+1))+1:
+		return 0, QuadError(Overflow)
+	case neg:
+		return int64(-u), nil // also correct for math.MinInt64
+	case u > uint64(max):
+		return 0, QuadError(Overflow)
+	}
+
+	return int64(u), nil
+}
+
+// toUnsigned returns a rounded to an integer, if it is in the range [0, max].
+//
+func (a Quad) toUnsigned(rounding RoundingMode, max uint64) (uint64, error) {
+
+	u, neg, err := a.magnitude(rounding)
+	if err != nil {
+		return 0, err
+	}
+
+	if neg || u > max {
+		return 0, QuadError(Overflow)
+	}
+
+	return u, nil
+}
+
+// In all the functions below, a is rounded to an integer with the rounding mode passed as argument.
+// E.g. 2.5 is 2 with RoundHalfEven, and 3 with RoundHalfUp. -0.4 is 0 with RoundHalfEven, and is not an error for unsigned types.
+//
+// If a is NaN or Infinity, 0 and QuadError(InvalidOperation) are returned.
+// If the rounded value is out of the range of the type, 0 and QuadError(Overflow) are returned.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+
+// ToInt returns a rounded to an int.
+//
+func (a Quad) ToInt(rounding RoundingMode) (int, error) {
+
+	v, err := a.toSigned(rounding, math.MinInt, math.MaxInt)
+	return int(v), err
+}
+
+// ToInt8 returns a rounded to an int8.
+//
+func (a Quad) ToInt8(rounding RoundingMode) (int8, error) {
+
+	v, err := a.toSigned(rounding, math.MinInt8, math.MaxInt8)
+	return int8(v), err
+}
+
+// ToInt16 returns a rounded to an int16.
+//
+func (a Quad) ToInt16(rounding RoundingMode) (int16, error) {
+
+	v, err := a.toSigned(rounding, math.MinInt16, math.MaxInt16)
+	return int16(v), err
+}
+
+// ToUint returns a rounded to an uint.
+//
+func (a Quad) ToUint(rounding RoundingMode) (uint, error) {
+
+	v, err := a.toUnsigned(rounding, math.MaxUint)
+	return uint(v), err
+}
+
+// ToUint8 returns a rounded to an uint8.
+//
+func (a Quad) ToUint8(rounding RoundingMode) (uint8, error) {
+
+	v, err := a.toUnsigned(rounding, math.MaxUint8)
+	return uint8(v), err
+}
+
+// ToUint16 returns a rounded to an uint16.
+//
+func (a Quad) ToUint16(rounding RoundingMode) (uint16, error) {
+
+	v, err := a.toUnsigned(rounding, math.MaxUint16)
+	return uint16(v), err
+}
+
+// ToUint32 returns a rounded to an uint32.
+//
+func (a Quad) ToUint32(rounding RoundingMode) (uint32, error) {
+
+	v, err := a.toUnsigned(rounding, math.MaxUint32)
+	return uint32(v), err
+}
+
+// ToUint64WithMode returns a rounded to an uint64.
+// Unlike ToUint64, it accepts a value with a fractional part.
+//
+func (a Quad) ToUint64WithMode(rounding RoundingMode) (uint64, error) {
+
+	return a.toUnsigned(rounding, math.MaxUint64)
+}
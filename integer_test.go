@@ -0,0 +1,145 @@
+package decnum
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestFromInteger(t *testing.T) {
+
+	type myInt int16
+
+	samples := []struct {
+		result   Quad
+		expected string
+	}{
+		{FromInteger(int8(-128)), "-128"},
+		{FromInteger(myInt(1234)), "1234"},
+		{FromInteger(math.MinInt64), "-9223372036854775808"},
+		{FromInteger(uint64(math.MaxUint64)), "18446744073709551615"},
+		{FromInteger(uint8(0)), "0"},
+		{FromInteger(uintptr(42)), "42"},
+		{FromUint64(math.MaxUint64), "18446744073709551615"},
+	}
+
+	for _, s := range samples {
+		if s.result.String() != s.expected || s.result.Status() != 0 {
+			t.Fatalf("FromInteger() = %s, status %s, expected %s", s.result, s.result.Status(), s.expected)
+		}
+	}
+}
+
+func TestToIntegers(t *testing.T) {
+
+	samples := []struct {
+		a        string
+		rounding RoundingMode
+		typ      string
+		expected string
+		err      error
+	}{
+		{"127", RoundHalfEven, "int8", "127", nil},
+		{"127.5", RoundHalfEven, "int8", "0", QuadError(Overflow)},
+		{"127.5", RoundDown, "int8", "127", nil},
+		{"-128.4", RoundHalfEven, "int8", "-128", nil},
+		{"-129", RoundHalfEven, "int8", "0", QuadError(Overflow)},
+		{"1.2E+2", RoundHalfEven, "int8", "120", nil},
+		{"32767.00", RoundHalfEven, "int16", "32767", nil},
+		{"-32768", RoundHalfEven, "int16", "-32768", nil},
+		{"3.2768E+4", RoundHalfEven, "int16", "0", QuadError(Overflow)},
+		{"-9223372036854775808", RoundHalfEven, "int", "-9223372036854775808", nil},
+		{"9223372036854775808", RoundHalfEven, "int", "0", QuadError(Overflow)},
+		{"2.5", RoundHalfEven, "int", "2", nil},
+		{"2.5", RoundHalfUp, "int", "3", nil},
+		{"-2.5", RoundFloor, "int", "-3", nil},
+		{"1E+30", RoundHalfEven, "int", "0", QuadError(Overflow)},
+		{"0E+5000", RoundHalfEven, "int", "0", nil},
+		{"NaN", RoundHalfEven, "int", "0", QuadError(InvalidOperation)},
+		{"-Inf", RoundHalfEven, "int16", "0", QuadError(InvalidOperation)},
+		{"255.4", RoundHalfEven, "uint8", "255", nil},
+		{"255.5", RoundHalfEven, "uint8", "0", QuadError(Overflow)},
+		{"-0.4", RoundHalfEven, "uint8", "0", nil},
+		{"-1", RoundHalfEven, "uint8", "0", QuadError(Overflow)},
+		{"65535", RoundHalfEven, "uint16", "65535", nil},
+		{"4294967295", RoundHalfEven, "uint32", "4294967295", nil},
+		{"4294967296", RoundHalfEven, "uint32", "0", QuadError(Overflow)},
+		{"18446744073709551615", RoundHalfEven, "uint", "18446744073709551615", nil},
+		{"18446744073709551615.5", RoundHalfEven, "uint64", "0", QuadError(Overflow)},
+		{"18446744073709551614.5", RoundHalfEven, "uint64", "18446744073709551614", nil},
+		{"1.8446744073709551615E+19", RoundHalfEven, "uint64", "18446744073709551615", nil},
+		{"Inf", RoundHalfEven, "uint64", "0", QuadError(InvalidOperation)},
+	}
+
+	for _, s := range samples {
+		var (
+			v   string
+			err error
+		)
+
+		a := must_quad(s.a)
+
+		switch s.typ {
+		case "int":
+			var r int
+			r, err = a.ToInt(s.rounding)
+			v = strconv.FormatInt(int64(r), 10)
+		case "int8":
+			var r int8
+			r, err = a.ToInt8(s.rounding)
+			v = strconv.FormatInt(int64(r), 10)
+		case "int16":
+			var r int16
+			r, err = a.ToInt16(s.rounding)
+			v = strconv.FormatInt(int64(r), 10)
+		case "uint":
+			var r uint
+			r, err = a.ToUint(s.rounding)
+			v = strconv.FormatUint(uint64(r), 10)
+		case "uint8":
+			var r uint8
+			r, err = a.ToUint8(s.rounding)
+			v = strconv.FormatUint(uint64(r), 10)
+		case "uint16":
+			var r uint16
+			r, err = a.ToUint16(s.rounding)
+			v = strconv.FormatUint(uint64(r), 10)
+		case "uint32":
+			var r uint32
+			r, err = a.ToUint32(s.rounding)
+			v = strconv.FormatUint(uint64(r), 10)
+		case "uint64":
+			var r uint64
+			r, err = a.ToUint64WithMode(s.rounding)
+			v = strconv.FormatUint(r, 10)
+		}
+
+		if v != s.expected || err != s.err {
+			t.Fatalf("%s to %s with %s = %s, %v, expected %s, %v", s.a, s.typ, s.rounding, v, err, s.expected, s.err)
+		}
+	}
+}
+
+func TestToUint64(t *testing.T) {
+
+	samples := []struct {
+		a        string
+		expected uint64
+		err      error
+	}{
+		{"123", 123, nil},
+		{"123.00", 123, nil},
+		{"1.5E+3", 1500, nil},
+		{"18446744073709551615", math.MaxUint64, nil},
+		{"123.5", 0, QuadError(InvalidOperation)},
+		{"-1", 0, QuadError(Overflow)},
+		{"18446744073709551616", 0, QuadError(Overflow)},
+		{"NaN", 0, QuadError(InvalidOperation)},
+	}
+
+	for _, s := range samples {
+		if r, err := must_quad(s.a).ToUint64(); r != s.expected || err != s.err {
+			t.Fatalf("%s.ToUint64() = %d, %v, expected %d, %v", s.a, r, err, s.expected, s.err)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package decnum
+
+import (
+	"sort"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                  invoice tax rounding reconciliation                 */
+/*                                                                      */
+/************************************************************************/
+
+// TaxLine is a line of the report returned by ReconcileTax.
+//
+type TaxLine struct {
+	Net        Quad // net amount of the line, as passed to ReconcileTax
+	ExactTax   Quad // Net * rate, not rounded
+	RoundedTax Quad // ExactTax rounded to the scale
+	Adjustment Quad // amount added to RoundedTax to reconcile with the total tax, 0 for most lines
+	Tax        Quad // tax of the line, RoundedTax + Adjustment
+}
+
+// TaxReport is returned by ReconcileTax.
+//
+// The sum of Lines[i].Tax is always equal to TotalTax.
+//
+type TaxReport struct {
+	Lines      []TaxLine
+	TotalNet   Quad  // sum of the net amounts
+	TotalTax   Quad  // TotalNet * rate, rounded to the scale
+	LineTax    Quad  // sum of the RoundedTax of the lines, before adjustment
+	Difference Quad  // TotalTax - LineTax, distributed to the lines
+	Adjusted   []int // indexes of the lines with a non-zero Adjustment, in increasing order
+}
+
+// ReconcileTax computes the tax of invoice lines, so that the tax of each line is rounded, and the sum of the line taxes equals the tax of the total.
+//
+// The tax of each line is net * rate, rounded with the rounding mode to scale fractional digits, e.g. rate 0.20 for 20%, and scale 2 for cents.
+// Note that rate is a fraction here, whereas AddTax, ExtractTax and NetFromGross take a percentage, e.g. 20 for 20%.
+// The total tax is the sum of the net amounts multiplied by rate, rounded in the same way.
+// The difference between the total tax and the sum of the rounded line taxes is then pushed into the lines, one unit (e.g. one cent) per line:
+// the units go to the lines whose tax was rounded the most in the opposite direction, that is, the lines with the largest rounding error.
+// Ties go to the first line, so that the result is deterministic.
+//
+//        lines 10.05 10.05 10.05, rate 0.20, scale 2, RoundHalfUp
+//        exact line tax 2.01 each, no adjustment
+//
+//        lines 0.33 0.33 0.33, rate 0.20, scale 2, RoundHalfUp
+//        exact line tax 0.066, rounded 0.07 each, sum 0.21
+//        total 0.99 * 0.20 = 0.198, rounded 0.20
+//        difference -0.01 is pushed into the first line: taxes are 0.06 0.07 0.07
+//
+// An error is returned if a net amount or the rate is not finite, or if a computation sets an error flag.
+//
+func ReconcileTax(lines []Quad, rate Quad, scale int32, rounding RoundingMode) (TaxReport, error) {
+	var report TaxReport
+
+	unit := unitQuad(clampExp(-int64(scale)))
+
+	if !rate.IsFinite() {
+		return report, newError(InvalidOperation)
+	}
+
+	report.Lines = make([]TaxLine, len(lines))
+	report.TotalNet = Zero()
+	report.LineTax = Zero().Quantize(unit, rounding)
+
+	for i, net := range lines {
+		if !net.IsFinite() {
+			return TaxReport{}, newError(InvalidOperation)
+		}
+
+		line := &report.Lines[i]
+		line.Net = net
+		line.ExactTax = net.Mul(rate)
+		line.RoundedTax = line.ExactTax.Quantize(unit, rounding)
+
+		report.TotalNet = report.TotalNet.Add(net)
+		report.LineTax = report.LineTax.Add(line.RoundedTax)
+	}
+
+	report.TotalTax = report.TotalNet.Mul(rate).Quantize(unit, rounding)
+	report.Difference = report.TotalTax.Sub(report.LineTax)
+
+	if err := report.Difference.Error(); err != nil {
+		return TaxReport{}, err
+	}
+
+	// distribute the difference, one unit per line
+
+	n, _ := report.Difference.Div(unit).ToInt64(RoundHalfEven) // exact integer
+
+	step := unit
+	if n < 0 {
+		step = unit.Neg()
+		n = -n
+	}
+
+	// error[i] is how much line i was rounded against the direction of the difference.
+	// E.g. if the difference is positive, the lines rounded down the most receive the units first.
+
+	errs := make([]Quad, len(lines))
+	order := make([]int, len(lines))
+	for i := range report.Lines {
+		errs[i] = report.Lines[i].ExactTax.Sub(report.Lines[i].RoundedTax)
+		if step.IsNegative() {
+			errs[i] = errs[i].Neg()
+		}
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return errs[order[i]].Greater(errs[order[j]])
+	})
+
+	zero := Zero().Quantize(unit, rounding)
+
+	for i := range report.Lines {
+		report.Lines[i].Adjustment = zero
+	}
+
+	for k := int64(0); k < n; k++ {
+		i := order[k%int64(len(order))]
+		report.Lines[i].Adjustment = report.Lines[i].Adjustment.Add(step)
+	}
+
+	for i := range report.Lines {
+		line := &report.Lines[i]
+		line.Tax = line.RoundedTax.Add(line.Adjustment)
+
+		if !line.Adjustment.IsZero() {
+			report.Adjusted = append(report.Adjusted, i)
+		}
+	}
+
+	return report, nil
+}
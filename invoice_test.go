@@ -0,0 +1,78 @@
+package decnum
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReconcileTax(t *testing.T) {
+
+	samples := []struct {
+		lines    string
+		rate     string
+		rounding RoundingMode
+		taxes    string
+		total    string
+		diff     string
+		adjusted []int
+	}{
+		{"10.05 10.05 10.05", "0.20", RoundHalfUp, "2.01 2.01 2.01", "6.03", "0.00", nil},
+		{"0.33 0.33 0.33", "0.20", RoundHalfUp, "0.06 0.07 0.07", "0.20", "-0.01", []int{0}},
+		{"0.12 0.12 0.12 0.12", "0.20", RoundHalfUp, "0.03 0.03 0.02 0.02", "0.10", "0.02", []int{0, 1}},
+		{"0.12 0.13 0.14 0.16", "0.20", RoundHalfUp, "0.02 0.03 0.03 0.03", "0.11", "0.00", nil},
+		{"1.01 2.02 3.03", "0.18", RoundHalfEven, "0.18 0.36 0.55", "1.09", "0.00", nil},
+		{"0.07 0.07 0.07 0.07 0.07", "0.10", RoundHalfUp, "0.00 0.01 0.01 0.01 0.01", "0.04", "-0.01", []int{0}},
+		{"0.07 0.07 0.07 0.07 0.07", "0.10", RoundDown, "0.01 0.01 0.01 0.00 0.00", "0.03", "0.03", []int{0, 1, 2}},
+		{"0.04 0.02 0.03", "0.25", RoundHalfUp, "0.01 0.00 0.01", "0.02", "-0.01", []int{1}},
+		{"-0.33 -0.33 -0.33", "0.20", RoundHalfUp, "-0.06 -0.07 -0.07", "-0.20", "0.01", []int{0}},
+		{"", "0.20", RoundHalfUp, "", "0.00", "0.00", nil},
+	}
+
+	for _, s := range samples {
+		var lines []Quad
+		for _, l := range strings.Fields(s.lines) {
+			lines = append(lines, must_quad(l))
+		}
+
+		report, err := ReconcileTax(lines, must_quad(s.rate), 2, s.rounding)
+		if err != nil {
+			t.Fatalf("ReconcileTax(%s) returned error %v", s.lines, err)
+		}
+
+		strs := make([]string, len(report.Lines))
+		sum := Zero()
+		for i, l := range report.Lines {
+			strs[i] = l.Tax.String()
+			sum = sum.Add(l.Tax)
+			if !l.Tax.Equal(l.RoundedTax.Add(l.Adjustment)) {
+				t.Fatalf("ReconcileTax(%s) line %d: Tax %s != RoundedTax %s + Adjustment %s", s.lines, i, l.Tax, l.RoundedTax, l.Adjustment)
+			}
+		}
+
+		if r := strings.Join(strs, " "); r != s.taxes {
+			t.Fatalf("ReconcileTax(%s) taxes = [%s], expected [%s]", s.lines, r, s.taxes)
+		}
+		if report.TotalTax.String() != s.total || report.Difference.String() != s.diff {
+			t.Fatalf("ReconcileTax(%s) total %s, difference %s, expected %s, %s", s.lines, report.TotalTax, report.Difference, s.total, s.diff)
+		}
+		if !sum.Equal(report.TotalTax) {
+			t.Fatalf("ReconcileTax(%s) line taxes sum to %s, expected %s", s.lines, sum, report.TotalTax)
+		}
+		if len(report.Adjusted) != len(s.adjusted) {
+			t.Fatalf("ReconcileTax(%s) adjusted lines %v, expected %v", s.lines, report.Adjusted, s.adjusted)
+		}
+		for i := range s.adjusted {
+			if report.Adjusted[i] != s.adjusted[i] {
+				t.Fatalf("ReconcileTax(%s) adjusted lines %v, expected %v", s.lines, report.Adjusted, s.adjusted)
+			}
+		}
+	}
+
+	if _, err := ReconcileTax([]Quad{must_quad("1"), NaN()}, must_quad("0.2"), 2, RoundHalfUp); err == nil {
+		t.Fatalf("ReconcileTax with NaN line must fail")
+	}
+
+	if _, err := ReconcileTax([]Quad{must_quad("1")}, must_quad("Inf"), 2, RoundHalfUp); err == nil {
+		t.Fatalf("ReconcileTax with infinite rate must fail")
+	}
+}
@@ -0,0 +1,173 @@
+package decnum
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                          JSON marshaling                             */
+/*                                                                      */
+/************************************************************************/
+
+// JSONSpecialPolicy tells how NaN and Infinity are written when a Quad is marshaled as a bare JSON number, as JSON has no representation for them.
+//
+type JSONSpecialPolicy int
+
+const (
+	JSONSpecialString JSONSpecialPolicy = iota // NaN and Infinity are written as JSON strings, "NaN", "Infinity", "-Infinity".
+	JSONSpecialNull                            // NaN and Infinity are written as null.
+	JSONSpecialError                           // Marshaling NaN or Infinity returns an error.
+)
+
+// JSONNullPolicy tells how JSON null is unmarshaled.
+//
+type JSONNullPolicy int
+
+const (
+	JSONNullIgnore JSONNullPolicy = iota // null leaves the Quad unchanged, as encoding/json does for other types.
+	JSONNullZero                         // null sets the Quad to 0.
+	JSONNullError                        // null returns an error.
+)
+
+// JSONOptions configures the JSON representation of a Quad.
+//
+// The zero value writes a bare JSON number, writes NaN and Infinity as JSON strings, and ignores null.
+//
+// Quad.MarshalJSON and Quad.UnmarshalJSON use JSONOptions{Quoted: true}.
+// JSONNumber.MarshalJSON and JSONNumber.UnmarshalJSON use JSONOptions{Special: JSONSpecialNull}.
+//
+type JSONOptions struct {
+	Quoted  bool              // if true, the number is written as a JSON string, e.g. "12.50". Else, as a bare JSON number, e.g. 12.50.
+	Special JSONSpecialPolicy // how NaN and Infinity are written if Quoted is false.
+	Null    JSONNullPolicy    // how null is unmarshaled.
+}
+
+var (
+	jsonQuoted = JSONOptions{Quoted: true}             // used by Quad
+	jsonNumber = JSONOptions{Special: JSONSpecialNull} // used by JSONNumber
+	jsonNull   = []byte("null")
+)
+
+// AppendJSON appends the JSON representation of a to dst.
+// The number is written as by String(), so that no digit is lost.
+//
+// The status field of a is not checked.
+//
+func (opts JSONOptions) AppendJSON(dst []byte, a Quad) ([]byte, error) {
+
+	if opts.Quoted {
+		dst = append(dst, '"')
+		dst = AppendQuad(dst, a)
+		return append(dst, '"'), nil
+	}
+
+	if !a.IsFinite() {
+		switch opts.Special {
+		case JSONSpecialNull:
+			return append(dst, jsonNull...), nil
+		case JSONSpecialError:
+			return dst, fmt.Errorf("decnum: %s cannot be marshaled as JSON number", a.String())
+		default:
+			dst = append(dst, '"')
+			dst = AppendQuad(dst, a)
+			return append(dst, '"'), nil
+		}
+	}
+
+	return AppendQuad(dst, a), nil
+}
+
+// Marshal returns the JSON representation of a.
+//
+func (opts JSONOptions) Marshal(a Quad) ([]byte, error) {
+
+	return opts.AppendJSON(nil, a)
+}
+
+// Unmarshal parses the JSON value in data, and stores the result in a.
+//
+// data can be a JSON number, a JSON string containing any string accepted by FromString, or null.
+// JSON numbers are parsed directly from their text, so that no precision is lost.
+//
+// If the value is not a valid number, a is set to the result of FromString, that is NaN with ConversionSyntax status, and an error is returned.
+//
+func (opts JSONOptions) Unmarshal(data []byte, a *Quad) error {
+	var (
+		str string
+		err error
+	)
+
+	data = bytes.TrimSpace(data)
+
+	switch {
+	case bytes.Equal(data, jsonNull):
+		switch opts.Null {
+		case JSONNullZero:
+			*a = Zero()
+		case JSONNullError:
+			return fmt.Errorf("decnum: cannot unmarshal JSON null into Quad")
+		}
+		return nil
+
+	case len(data) != 0 && data[0] == '"':
+		if err = json.Unmarshal(data, &str); err != nil { // handles escape sequences
+			return fmt.Errorf("Error decoding string '%s': %s", data, err)
+		}
+
+	default:
+		str = string(data)
+	}
+
+	d, err := FromString(str)
+	*a = d
+	if err != nil {
+		return fmt.Errorf("Error decoding string '%s': %s", str, err)
+	}
+	return nil
+}
+
+// FromJSONNumber returns a Quad from a json.Number, as produced by json.Decoder.UseNumber().
+// No precision is lost, as the number is parsed from its text.
+//
+// This function returns result.Error() as a convenience.
+//
+func FromJSONNumber(n json.Number) (result Quad, err error) {
+
+	return FromString(string(n))
+}
+
+// JSONNumber is a Quad that is marshaled as a bare JSON number, e.g. 12.50 instead of "12.50".
+// NaN and Infinity are marshaled as null.
+//
+// Use it as field type in structs marshaled for APIs expecting JSON numbers:
+//
+//        type Invoice struct {
+//            Total decnum.JSONNumber `json:"total"`
+//        }
+//
+//        inv.Total = decnum.JSONNumber(q)
+//        q = decnum.Quad(inv.Total)
+//
+type JSONNumber Quad
+
+// MarshalJSON implements the json.Marshaler interface.
+func (n JSONNumber) MarshalJSON() ([]byte, error) {
+
+	return jsonNumber.AppendJSON(nil, Quad(n))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts JSON numbers, JSON strings and null, which leaves n unchanged.
+func (n *JSONNumber) UnmarshalJSON(data []byte) error {
+
+	return jsonNumber.Unmarshal(data, (*Quad)(n))
+}
+
+// String returns Quad(n).String().
+func (n JSONNumber) String() string {
+
+	return Quad(n).String()
+}
@@ -0,0 +1,114 @@
+package decnum
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func Test_json_options(t *testing.T) {
+
+	var samples = []struct {
+		opts     JSONOptions
+		a        string
+		expected string // "" if error expected
+	}{
+		{JSONOptions{Quoted: true}, "12.50", `"12.50"`},
+		{JSONOptions{Quoted: true}, "NaN", `"NaN"`},
+		{JSONOptions{}, "12.50", `12.50`},
+		{JSONOptions{}, "-1E+100", `-1E+100`},
+		{JSONOptions{}, "Inf", `"Infinity"`},
+		{JSONOptions{}, "-Inf", `"-Infinity"`},
+		{JSONOptions{Special: JSONSpecialNull}, "NaN", `null`},
+		{JSONOptions{Special: JSONSpecialNull}, "0.000", `0.000`},
+		{JSONOptions{Special: JSONSpecialError}, "NaN", ""},
+		{JSONOptions{Special: JSONSpecialError}, "1", `1`},
+	}
+
+	for _, s := range samples {
+		b, err := s.opts.Marshal(must_quad(s.a))
+		if string(b) != s.expected || (err != nil) != (s.expected == "") {
+			t.Fatalf("%+v.Marshal(%s) = %s %v, expected %s", s.opts, s.a, b, err, s.expected)
+		}
+
+		if s.expected == "" || s.expected == "null" {
+			continue
+		}
+
+		var back Quad
+		if err = s.opts.Unmarshal(b, &back); err != nil || back.String() != must_quad(s.a).String() {
+			t.Fatalf("%+v.Unmarshal(%s) = %s %v", s.opts, b, back, err)
+		}
+	}
+
+	// null policies
+
+	a := must_quad("5")
+	if err := (JSONOptions{}).Unmarshal([]byte("null"), &a); err != nil || a.String() != "5" {
+		t.Fatalf("null with JSONNullIgnore gives %s %v", a, err)
+	}
+	if err := (JSONOptions{Null: JSONNullZero}).Unmarshal([]byte(" null "), &a); err != nil || a.String() != "0" {
+		t.Fatalf("null with JSONNullZero gives %s %v", a, err)
+	}
+	if err := (JSONOptions{Null: JSONNullError}).Unmarshal([]byte("null"), &a); err == nil {
+		t.Fatal("null with JSONNullError should fail")
+	}
+
+	// escapes in strings, and syntax errors
+
+	if err := (JSONOptions{}).Unmarshal([]byte(`"\u0031.5"`), &a); err != nil || a.String() != "1.5" {
+		t.Fatalf("unmarshal of escaped string gives %s %v", a, err)
+	}
+	if err := (JSONOptions{}).Unmarshal([]byte(`"1.5`), &a); err == nil {
+		t.Fatal("unmarshal of unterminated string should fail")
+	}
+	if err := (JSONOptions{}).Unmarshal([]byte(`true`), &a); err == nil || !a.IsNaN() {
+		t.Fatal("unmarshal of true should fail")
+	}
+}
+
+func Test_json_struct(t *testing.T) {
+
+	type invoice struct {
+		Total  Quad       `json:"total"`
+		Amount JSONNumber `json:"amount"`
+		Tax    *Quad      `json:"tax"`
+	}
+
+	in := invoice{Total: must_quad("1234567890.123456789012345678901234"), Amount: JSONNumber(must_quad("-0.10"))}
+
+	b, err := json.Marshal(in)
+	if err != nil || string(b) != `{"total":"1234567890.123456789012345678901234","amount":-0.10,"tax":null}` {
+		t.Fatalf("json.Marshal = %s %v", b, err)
+	}
+
+	var out invoice
+	out.Total = must_quad("7")
+	if err = json.Unmarshal([]byte(`{"total":null,"amount":1234567890.123456789012345678901234,"tax":"0.2"}`), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Total.String() != "7" || Quad(out.Amount).String() != "1234567890.123456789012345678901234" || out.Tax.String() != "0.2" {
+		t.Fatalf("json.Unmarshal = %v", out)
+	}
+
+	b, _ = json.Marshal(JSONNumber(must_quad("NaN")))
+	if string(b) != "null" {
+		t.Fatalf("json.Marshal(JSONNumber(NaN)) = %s", b)
+	}
+
+	// json.Number keeps all digits
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(`{"v": 0.1234567890123456789012345678901234}`)))
+	dec.UseNumber()
+
+	var m map[string]interface{}
+	if err = dec.Decode(&m); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := FromJSONNumber(m["v"].(json.Number))
+	if err != nil || q.String() != "0.1234567890123456789012345678901234" {
+		t.Fatalf("FromJSONNumber = %s %v", q, err)
+	}
+}
@@ -0,0 +1,177 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+import (
+	"encoding/binary"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                 text, binary and gob marshaling                      */
+/*                                                                      */
+/************************************************************************/
+
+// Binary format written by MarshalBinary and AppendBinaryWithStatus:
+//
+//        byte 0          version, binaryVersion1
+//        byte 1          flags. binaryFlagStatus is set if the status follows the value, only written by AppendBinaryWithStatus.
+//        bytes 2..17     value, in IEEE 754 decimal128 interchange format (DPD), most significant byte first
+//        bytes 18..19    status, big-endian. Only present if binaryFlagStatus is set.
+//
+const (
+	binaryVersion1   = 1
+	binaryFlagStatus = 0x01
+
+	binaryHeaderLen = 2
+)
+
+var nativeBigEndian = isBigEndian(binary.NativeEndian) // decQuad value is stored in platform byte order, checked by C.mdq_init()
+
+// AppendText implements the encoding.TextAppender interface.
+// It appends the same string as String().
+//
+func (a Quad) AppendText(b []byte) ([]byte, error) {
+
+	return AppendQuad(b, a), nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// It returns the same string as String().
+//
+// Quad can be used as map key with encoding/json, and with XML, flags or YAML libraries.
+//
+func (a Quad) MarshalText() ([]byte, error) {
+
+	return AppendQuad(nil, a), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It accepts the same strings as FromString.
+//
+// The Quad is set even if an error is returned, and the error is a.Error().
+//
+func (a *Quad) UnmarshalText(text []byte) error {
+	var err error
+
+	*a, err = FromString(string(text))
+
+	return err
+}
+
+// AppendBinary implements the encoding.BinaryAppender interface.
+// See MarshalBinary for the format. The status is not written.
+//
+func (a Quad) AppendBinary(b []byte) ([]byte, error) {
+
+	return a.appendBinary(b, false), nil
+}
+
+// AppendBinaryWithStatus appends the same format as AppendBinary, followed by the status of a if it is not 0, so that UnmarshalBinary restores it.
+// The result is 20 bytes long if the status is not 0.
+//
+func (a Quad) AppendBinaryWithStatus(b []byte) []byte {
+
+	return a.appendBinary(b, true)
+}
+
+// appendBinary appends the binary format of a to b, with the status only if withStatus is true and the status is not 0.
+//
+func (a Quad) appendBinary(b []byte, withStatus bool) []byte {
+	var (
+		val   [DecquadBytes]byte
+		flags byte
+	)
+
+	val = a.Bytes()
+
+	if !nativeBigEndian {
+		reverseBytes(val[:])
+	}
+
+	if withStatus && a.status != 0 {
+		flags |= binaryFlagStatus
+	}
+
+	b = append(b, binaryVersion1, flags)
+	b = append(b, val[:]...)
+
+	if flags&binaryFlagStatus != 0 {
+		b = binary.BigEndian.AppendUint16(b, uint16(a.status))
+	}
+
+	return b
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+//
+// The format is versioned and compact: a version byte, a flags byte, and the 16 bytes of the value in IEEE 754 decimal128 format (most significant byte first).
+// So, the result is 18 bytes long, and the same value is always encoded the same way, e.g. for gob or binary caches.
+//
+// The status is not written. Use AppendBinaryWithStatus to keep it.
+//
+func (a Quad) MarshalBinary() ([]byte, error) {
+
+	return a.appendBinary(make([]byte, 0, binaryHeaderLen+DecquadBytes), false), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// It decodes the format written by MarshalBinary, and by AppendBinaryWithStatus. The status of a is set to the status written, or to 0.
+//
+// ConversionSyntax error is returned if data is not valid. In this case, a is not modified.
+//
+func (a *Quad) UnmarshalBinary(data []byte) error {
+	var (
+		val    [DecquadBytes]byte
+		status uint16
+	)
+
+	if len(data) < binaryHeaderLen || data[0] != binaryVersion1 || data[1]&^binaryFlagStatus != 0 {
+		return QuadError(ConversionSyntax)
+	}
+
+	expectedLen := binaryHeaderLen + DecquadBytes
+	if data[1]&binaryFlagStatus != 0 {
+		expectedLen += 2
+	}
+
+	if len(data) != expectedLen {
+		return QuadError(ConversionSyntax)
+	}
+
+	copy(val[:], data[binaryHeaderLen:])
+
+	if !nativeBigEndian {
+		reverseBytes(val[:])
+	}
+
+	if data[1]&binaryFlagStatus != 0 {
+		status = binary.BigEndian.Uint16(data[binaryHeaderLen+DecquadBytes:])
+	}
+
+	for i := range a.val {
+		a.val[i] = val[i]
+	}
+	a.status = C.uint16_t(status)
+
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+// It uses the same format as MarshalBinary, without the status.
+//
+func (a Quad) GobEncode() ([]byte, error) {
+
+	return a.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+//
+func (a *Quad) GobDecode(data []byte) error {
+
+	return a.UnmarshalBinary(data)
+}
@@ -0,0 +1,147 @@
+package decnum
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+)
+
+func Test_marshal_text(t *testing.T) {
+
+	for _, s := range []string{"0", "123.4500", "-1E+100", "Infinity", "-Infinity", "NaN", "NaN123", "1E-6176"} {
+		a := must_quad(s)
+
+		text, err := a.MarshalText()
+		if err != nil || string(text) != a.String() {
+			t.Fatalf("MarshalText(%s) = %s %v", s, text, err)
+		}
+
+		var b Quad
+		if err = b.UnmarshalText(text); err != nil || b.String() != a.String() {
+			t.Fatalf("UnmarshalText(%s) = %s %v", text, b, err)
+		}
+	}
+
+	var b Quad
+	if err := b.UnmarshalText([]byte("1,5")); err == nil || b.Status()&ConversionSyntax == 0 {
+		t.Fatal("UnmarshalText(\"1,5\") should fail")
+	}
+
+	// map key with encoding/json
+
+	m := map[Quad]int{must_quad("1.50"): 1, must_quad("-2"): 2}
+
+	js, err := json.Marshal(m)
+	if err != nil || string(js) != `{"-2":2,"1.50":1}` {
+		t.Fatalf("json.Marshal(map) = %s %v", js, err)
+	}
+
+	m2 := map[Quad]int{}
+	if err = json.Unmarshal(js, &m2); err != nil || m2[must_quad("1.50")] != 1 || m2[must_quad("-2")] != 2 {
+		t.Fatalf("json.Unmarshal(%s) = %v %v", js, m2, err)
+	}
+
+	// XML
+
+	type item struct {
+		Price Quad `xml:"price,attr"`
+		Qty   Quad `xml:"qty"`
+	}
+
+	x, err := xml.Marshal(item{must_quad("12.30"), must_quad("5")})
+	if err != nil || string(x) != `<item price="12.30"><qty>5</qty></item>` {
+		t.Fatalf("xml.Marshal = %s %v", x, err)
+	}
+
+	var it item
+	if err = xml.Unmarshal(x, &it); err != nil || it.Price.String() != "12.30" || it.Qty.String() != "5" {
+		t.Fatalf("xml.Unmarshal(%s) = %v %v", x, it, err)
+	}
+}
+
+func Test_marshal_binary(t *testing.T) {
+
+	var samples = []struct {
+		a        Quad
+		expected string // hex
+	}{
+		{must_quad("0"), "0100" + "22080000000000000000000000000000"},
+		{must_quad("1"), "0100" + "22080000000000000000000000000001"},
+		{must_quad("-7.50"), "0100" + "a20780000000000000000000000003d0"}, // 750E-2, 750 is 0x3d0 in DPD
+		{must_quad("NaN"), "0100" + "7c000000000000000000000000000000"},
+		{must_quad("1").SetStatusFlags(Inexact | DivisionByZero), "0100" + "22080000000000000000000000000001"}, // status is not written
+	}
+
+	for _, s := range samples {
+		b, err := s.a.MarshalBinary()
+		if err != nil || hex.EncodeToString(b) != s.expected {
+			t.Fatalf("MarshalBinary(%s) = %x %v, expected %s", s.a, b, err, s.expected)
+		}
+
+		var back Quad
+		if err = back.UnmarshalBinary(b); err != nil || back != s.a.ClearStatus() {
+			t.Fatalf("UnmarshalBinary(%x) = %#v %v, expected %#v", b, back, err, s.a.ClearStatus())
+		}
+	}
+
+	var withStatus = []struct {
+		a        Quad
+		expected string // hex
+	}{
+		{must_quad("1"), "0100" + "22080000000000000000000000000001"},
+		{must_quad("1").SetStatusFlags(Inexact | DivisionByZero), "0101" + "22080000000000000000000000000001" + "0022"},
+	}
+
+	for _, s := range withStatus {
+		b := s.a.AppendBinaryWithStatus(nil)
+		if hex.EncodeToString(b) != s.expected {
+			t.Fatalf("AppendBinaryWithStatus(%s) = %x, expected %s", s.a, b, s.expected)
+		}
+
+		var back Quad
+		if err := back.UnmarshalBinary(b); err != nil || back != s.a {
+			t.Fatalf("UnmarshalBinary(%x) = %#v %v, expected %#v", b, back, err, s.a)
+		}
+	}
+
+	for _, bad := range []string{"", "01", "0200" + "22080000000000000000000000000001", "0101" + "22080000000000000000000000000001", "0102" + "22080000000000000000000000000001", "0100" + "2208000000000000000000000000000100"} {
+		raw, _ := hex.DecodeString(bad)
+		a := One()
+		if err := a.UnmarshalBinary(raw); err == nil || !a.Equal(One()) {
+			t.Fatalf("UnmarshalBinary(%s) should fail", bad)
+		}
+	}
+}
+
+func Test_gob(t *testing.T) {
+
+	type record struct {
+		Amount Quad
+		List   []Quad
+	}
+
+	in := record{must_quad("-123.456"), []Quad{must_quad("1E+10"), must_quad("sNaN"), must_quad("1").Div(must_quad("3"))}}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+
+	var out record
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Amount != in.Amount || len(out.List) != len(in.List) {
+		t.Fatalf("gob decoded %v, expected %v", out, in)
+	}
+
+	for i := range in.List {
+		if out.List[i] != in.List[i].ClearStatus() { // status is not encoded
+			t.Fatalf("gob decoded %v, expected %v", out.List[i], in.List[i])
+		}
+	}
+}
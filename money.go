@@ -0,0 +1,343 @@
+package decnum
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                   Money, amount with currency code                   */
+/*                                                                      */
+/************************************************************************/
+
+// Errors returned by Money functions and methods.
+//
+var (
+	ErrCurrencyMismatch = errors.New("decnum: currency mismatch")
+	ErrInvalidCurrency  = errors.New("decnum: invalid currency code")
+)
+
+// minorUnitsExceptions contains the ISO 4217 currencies whose minor unit is not 2 digits.
+//
+var minorUnitsExceptions = map[string]int32{
+	// no minor unit
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+
+	// 3 digits
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+
+	// 4 digits
+	"CLF": 4, "UYW": 4,
+}
+
+// MinorUnits returns the number of digits of the minor unit of the currency, as defined by ISO 4217, e.g. 2 for USD, 0 for JPY, 3 for BHD.
+// It returns 2 for any valid code not listed with another number of digits in ISO 4217.
+//
+// ok is false if code is not made of 3 upper case letters.
+//
+func MinorUnits(code string) (digits int32, ok bool) {
+
+	if !isCurrencyCode(code) {
+		return 0, false
+	}
+
+	if digits, found := minorUnitsExceptions[code]; found {
+		return digits, true
+	}
+	return 2, true
+}
+
+// isCurrencyCode returns true if code is made of 3 upper case ASCII letters.
+//
+func isCurrencyCode(code string) bool {
+
+	if len(code) != 3 {
+		return false
+	}
+	for i := 0; i < 3; i++ {
+		if code[i] < 'A' || code[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Money is an amount in a currency identified by its ISO 4217 code, e.g. 12.50 USD.
+//
+// Like Quad, Money is immutable, and is passed by value.
+// Arithmetic between two Money values returns ErrCurrencyMismatch if their currencies are different, so that USD are never added to EUR by mistake.
+//
+// The zero value has no currency, and is only useful as a destination for unmarshaling.
+//
+type Money struct {
+	amount   Quad
+	currency string
+}
+
+// NewMoney returns a Money with the amount and currency passed as arguments.
+// The amount is not rounded. Use Round to round it to the minor unit of the currency.
+//
+// ErrInvalidCurrency is returned if currency is not made of 3 upper case letters.
+//
+func NewMoney(amount Quad, currency string) (Money, error) {
+
+	if !isCurrencyCode(currency) {
+		return Money{}, ErrInvalidCurrency
+	}
+
+	return Money{amount: amount, currency: currency}, nil
+}
+
+// MoneyFromString returns a Money from the amount string, as accepted by FromString, and the currency.
+//
+func MoneyFromString(amount string, currency string) (Money, error) {
+
+	a, err := FromString(amount)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return NewMoney(a, currency)
+}
+
+// Amount returns the amount of m.
+//
+func (m Money) Amount() Quad {
+
+	return m.amount
+}
+
+// Currency returns the ISO 4217 code of the currency of m.
+//
+func (m Money) Currency() string {
+
+	return m.currency
+}
+
+// MinorUnits returns the number of digits of the minor unit of the currency of m.
+//
+func (m Money) MinorUnits() int32 {
+
+	digits, _ := MinorUnits(m.currency)
+	return digits
+}
+
+// Error returns m.Amount().Error().
+//
+func (m Money) Error() error {
+
+	return m.amount.Error()
+}
+
+// Add returns m + b.
+// ErrCurrencyMismatch is returned if the currencies are different.
+// Else, the error is the error of the resulting amount, as returned by Quad.Error().
+//
+func (m Money) Add(b Money) (Money, error) {
+
+	if m.currency != b.currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	r := Money{amount: m.amount.Add(b.amount), currency: m.currency}
+	return r, r.amount.Error()
+}
+
+// Sub returns m - b.
+// ErrCurrencyMismatch is returned if the currencies are different.
+// Else, the error is the error of the resulting amount, as returned by Quad.Error().
+//
+func (m Money) Sub(b Money) (Money, error) {
+
+	if m.currency != b.currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	r := Money{amount: m.amount.Sub(b.amount), currency: m.currency}
+	return r, r.amount.Error()
+}
+
+// Mul returns m * factor, in the currency of m. The result is not rounded to the minor unit.
+//
+func (m Money) Mul(factor Quad) Money {
+
+	return Money{amount: m.amount.Mul(factor), currency: m.currency}
+}
+
+// Div returns m / divisor, in the currency of m. The result is not rounded to the minor unit.
+//
+func (m Money) Div(divisor Quad) Money {
+
+	return Money{amount: m.amount.Div(divisor), currency: m.currency}
+}
+
+// Neg returns -m.
+//
+func (m Money) Neg() Money {
+
+	return Money{amount: m.amount.Neg(), currency: m.currency}
+}
+
+// Abs returns the absolute value of m.
+//
+func (m Money) Abs() Money {
+
+	return Money{amount: m.amount.Abs(), currency: m.currency}
+}
+
+// Round rounds the amount of m to the minor unit of its currency, e.g. 2 digits for USD, 0 for JPY, 3 for BHD.
+// It calls RoundWithMode.
+//
+func (m Money) Round(rounding RoundingMode) Money {
+
+	return Money{amount: m.amount.RoundWithMode(m.MinorUnits(), rounding), currency: m.currency}
+}
+
+// Cmp compares m and b, and returns -1 if m < b, 0 if m == b, and 1 if m > b.
+// ErrCurrencyMismatch is returned if the currencies are different.
+// InvalidOperation error is returned if an amount is NaN.
+//
+func (m Money) Cmp(b Money) (int, error) {
+
+	switch {
+	case m.currency != b.currency:
+		return 0, ErrCurrencyMismatch
+	case m.amount.IsNaN() || b.amount.IsNaN():
+		return 0, newError(InvalidOperation)
+	case m.amount.Less(b.amount):
+		return -1, nil
+	case m.amount.Greater(b.amount):
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Equal returns true if m and b have the same currency and equal amounts. 1.5 USD is equal to 1.50 USD.
+//
+func (m Money) Equal(b Money) bool {
+
+	return m.currency == b.currency && m.amount.Equal(b.amount)
+}
+
+// IsZero returns true if the amount of m is zero.
+//
+func (m Money) IsZero() bool {
+
+	return m.amount.IsZero()
+}
+
+// IsNegative returns true if the amount of m is negative.
+//
+func (m Money) IsNegative() bool {
+
+	return m.amount.IsNegative()
+}
+
+// String returns the amount and the currency, separated by a space, e.g. "12.50 USD".
+//
+func (m Money) String() string {
+
+	return string(m.appendText(nil))
+}
+
+func (m Money) appendText(dst []byte) []byte {
+
+	dst = AppendQuad(dst, m.amount)
+	dst = append(dst, ' ')
+	return append(dst, m.currency...)
+}
+
+// AppendText implements the encoding.TextAppender interface.
+// It appends the same string as String(), e.g. "12.50 USD".
+//
+func (m Money) AppendText(b []byte) ([]byte, error) {
+
+	return m.appendText(b), nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// It returns the same string as String(), e.g. "12.50 USD".
+//
+func (m Money) MarshalText() ([]byte, error) {
+
+	return m.appendText(nil), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It accepts the amount and the currency separated by a space, e.g. "12.50 USD", as written by MarshalText.
+//
+// If an error is returned, m is not modified.
+//
+func (m *Money) UnmarshalText(text []byte) error {
+
+	amount, currency, found := strings.Cut(strings.TrimSpace(string(text)), " ")
+	if !found {
+		return fmt.Errorf("decnum: cannot unmarshal %q into Money: currency missing", text)
+	}
+
+	r, err := MoneyFromString(amount, strings.TrimSpace(currency))
+	if err != nil {
+		return fmt.Errorf("decnum: cannot unmarshal %q into Money: %w", text, err)
+	}
+
+	*m = r
+	return nil
+}
+
+// moneyJSON is the JSON representation of Money, written by MarshalJSON.
+//
+type moneyJSON struct {
+	Amount   Quad   `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// Money is written as a JSON object, with the amount as string to keep all its digits, e.g. {"amount":"12.50","currency":"USD"}.
+//
+func (m Money) MarshalJSON() ([]byte, error) {
+
+	return json.Marshal(moneyJSON{Amount: m.amount, Currency: m.currency})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts the object written by MarshalJSON. The amount can also be a JSON number.
+// null leaves m unchanged.
+//
+// Both "amount" and "currency" are required: an error is returned if one of them is missing or null.
+// If an error is returned, m is not modified.
+//
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var v struct { // pointers, to detect missing fields
+		Amount   *Quad   `json:"amount"`
+		Currency *string `json:"currency"`
+	}
+
+	if bytes.Equal(bytes.TrimSpace(data), jsonNull) {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	switch {
+	case v.Amount == nil:
+		return fmt.Errorf("decnum: cannot unmarshal %s into Money: amount missing", data)
+	case v.Currency == nil:
+		return fmt.Errorf("decnum: cannot unmarshal %s into Money: currency missing", data)
+	}
+
+	r, err := NewMoney(*v.Amount, *v.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = r
+	return nil
+}
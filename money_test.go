@@ -0,0 +1,178 @@
+package decnum
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func must_money(amount string, currency string) Money {
+
+	m, err := MoneyFromString(amount, currency)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func TestMinorUnits(t *testing.T) {
+
+	samples := []struct {
+		code   string
+		digits int32
+		ok     bool
+	}{
+		{"USD", 2, true},
+		{"EUR", 2, true},
+		{"RUB", 2, true},
+		{"JPY", 0, true},
+		{"KRW", 0, true},
+		{"BHD", 3, true},
+		{"KWD", 3, true},
+		{"CLF", 4, true},
+		{"usd", 0, false},
+		{"US", 0, false},
+		{"", 0, false},
+	}
+
+	for _, s := range samples {
+		digits, ok := MinorUnits(s.code)
+		if digits != s.digits || ok != s.ok {
+			t.Fatalf("MinorUnits(%q) = %d, %v, expected %d, %v", s.code, digits, ok, s.digits, s.ok)
+		}
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+
+	a := must_money("10.25", "USD")
+	b := must_money("0.75", "USD")
+	e := must_money("1", "EUR")
+
+	r, err := a.Add(b)
+	if err != nil || r.String() != "11.00 USD" {
+		t.Fatalf("Add = %s, %v, expected 11.00 USD", r, err)
+	}
+
+	r, err = a.Sub(b)
+	if err != nil || r.String() != "9.50 USD" {
+		t.Fatalf("Sub = %s, %v, expected 9.50 USD", r, err)
+	}
+
+	if _, err = a.Add(e); err != ErrCurrencyMismatch {
+		t.Fatalf("Add USD to EUR returned %v, expected ErrCurrencyMismatch", err)
+	}
+
+	if _, err = a.Sub(e); err != ErrCurrencyMismatch {
+		t.Fatalf("Sub EUR from USD returned %v, expected ErrCurrencyMismatch", err)
+	}
+
+	if _, err = a.Cmp(e); err != ErrCurrencyMismatch {
+		t.Fatalf("Cmp USD and EUR returned %v, expected ErrCurrencyMismatch", err)
+	}
+
+	if c, err := a.Cmp(b); c != 1 || err != nil {
+		t.Fatalf("Cmp = %d, %v, expected 1", c, err)
+	}
+
+	if r = a.Mul(must_quad("3")).Neg(); r.String() != "-30.75 USD" {
+		t.Fatalf("Mul Neg = %s, expected -30.75 USD", r)
+	}
+
+	if r = a.Div(must_quad("3")).Round(RoundHalfUp); r.String() != "3.42 USD" {
+		t.Fatalf("Div Round = %s, expected 3.42 USD", r)
+	}
+
+	if !must_money("1.5", "USD").Equal(must_money("1.50", "USD")) || must_money("1", "USD").Equal(e) {
+		t.Fatalf("Equal failed")
+	}
+
+	if _, err = NewMoney(One(), "usd"); err != ErrInvalidCurrency {
+		t.Fatalf("NewMoney with invalid code returned %v, expected ErrInvalidCurrency", err)
+	}
+}
+
+func TestMoneyRound(t *testing.T) {
+
+	samples := []struct {
+		amount   string
+		currency string
+		rounding RoundingMode
+		expected string
+	}{
+		{"1.005", "USD", RoundHalfUp, "1.01 USD"},
+		{"1.005", "USD", RoundHalfEven, "1.00 USD"},
+		{"1", "EUR", RoundHalfEven, "1.00 EUR"},
+		{"1234.5", "JPY", RoundHalfUp, "1235 JPY"},
+		{"1234.5", "JPY", RoundDown, "1234 JPY"},
+		{"1.23456", "BHD", RoundHalfUp, "1.235 BHD"},
+		{"-2.34567", "CLF", RoundFloor, "-2.3457 CLF"},
+	}
+
+	for _, s := range samples {
+		r := must_money(s.amount, s.currency).Round(s.rounding)
+		if r.String() != s.expected {
+			t.Fatalf("%s %s Round(%s) = %s, expected %s", s.amount, s.currency, s.rounding, r, s.expected)
+		}
+	}
+}
+
+func TestMoneyMarshal(t *testing.T) {
+	var (
+		m Money
+		v struct {
+			Price Money
+		}
+	)
+
+	a := must_money("12.50", "EUR")
+
+	b, err := json.Marshal(a)
+	if err != nil || string(b) != `{"amount":"12.50","currency":"EUR"}` {
+		t.Fatalf("json.Marshal = %s, %v", b, err)
+	}
+
+	if err = json.Unmarshal(b, &m); err != nil || !m.Equal(a) || m.String() != "12.50 EUR" {
+		t.Fatalf("json.Unmarshal = %s, %v", m, err)
+	}
+
+	if err = json.Unmarshal([]byte(`{"amount":99.999,"currency":"BHD"}`), &m); err != nil || m.String() != "99.999 BHD" {
+		t.Fatalf("json.Unmarshal with JSON number = %s, %v", m, err)
+	}
+
+	if err = json.Unmarshal([]byte(`null`), &m); err != nil || m.String() != "99.999 BHD" {
+		t.Fatalf("json.Unmarshal null = %s, %v, expected unchanged", m, err)
+	}
+
+	for _, bad := range []string{`{"amount":"1"}`, `{"currency":"USD"}`, `{}`, `{"amount":null,"currency":"USD"}`, `{"amount":"1","currency":null}`, `{"amount":"abc","currency":"USD"}`, `{"amount":"1","currency":"us"}`, `[]`} {
+		if err = json.Unmarshal([]byte(bad), &m); err == nil {
+			t.Fatalf("json.Unmarshal(%s) must fail", bad)
+		}
+		if m.String() != "99.999 BHD" {
+			t.Fatalf("json.Unmarshal(%s) modified the value: %s", bad, m)
+		}
+	}
+
+	b, err = a.MarshalText()
+	if err != nil || string(b) != "12.50 EUR" {
+		t.Fatalf("MarshalText = %s, %v", b, err)
+	}
+
+	if err = m.UnmarshalText([]byte("-7 JPY")); err != nil || m.String() != "-7 JPY" {
+		t.Fatalf("UnmarshalText = %s, %v", m, err)
+	}
+
+	for _, bad := range []string{"12.50", "12.50 eur", "x EUR", ""} {
+		if err = m.UnmarshalText([]byte(bad)); err == nil || m.String() != "-7 JPY" {
+			t.Fatalf("UnmarshalText(%q) = %s, %v, expected error", bad, m, err)
+		}
+	}
+
+	b, err = json.Marshal(map[Money]int{a: 1})
+	if err != nil || string(b) != `{"12.50 EUR":1}` {
+		t.Fatalf("json.Marshal map key = %s, %v", b, err)
+	}
+
+	if err = json.Unmarshal([]byte(`{"Price":{"amount":"3.5","currency":"USD"}}`), &v); err != nil || v.Price.String() != "3.5 USD" {
+		t.Fatalf("json.Unmarshal struct field = %s, %v", v.Price, err)
+	}
+}
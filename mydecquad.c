@@ -245,6 +245,40 @@ Quad mdq_divide(Quad a, Quad b) {
 }
 
 
+/* multiplication, with rounding mode.
+*/
+Quad mdq_multiplyM(Quad a, Quad b, int round) {
+  decContext  set;
+  Quad        res;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+  decContextSetRounding(&set, round);      // change rounding mode
+  set.status = a.status | b.status;
+
+  decQuadMultiply(&res.val, &a.val, &b.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* division, with rounding mode.
+*/
+Quad mdq_divideM(Quad a, Quad b, int round) {
+  decContext  set;
+  Quad        res;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+  decContextSetRounding(&set, round);      // change rounding mode
+  set.status = a.status | b.status;
+
+  decQuadDivide(&res.val, &a.val, &b.val, &set);
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
 /* integer division.
 */
 Quad mdq_divide_integer(Quad a, Quad b) {
@@ -513,6 +547,106 @@ Quad mdq_from_int64(int64_t value) {
 }
 
 
+/* conversion from BCD array.
+
+   bcd contains length digits, one digit per byte, most significant digit first. Leading zeros are allowed.
+   length must be in [1..MDQ_BCD_MAX].
+
+   The value is (-1)^sign * coefficient * 10^exp.
+   If the coefficient has more than DECQUAD_Pmax digits, it is rounded with the rounding mode passed as argument, and Inexact is set.
+   If the exponent is out of range, Overflow or Underflow is set.
+*/
+Quad mdq_from_BCD(const uint8_t *bcd, int32_t length, int32_t exp, uint32_t sign, int round) {
+  struct {                                            // decNumber large enough for MDQ_BCD_MAX digits
+      int32_t        digits;
+      int32_t        exponent;
+      uint8_t        bits;
+      decNumberUnit  lsu[(MDQ_BCD_MAX+DECDPUN-1)/DECDPUN];
+  }            wide;
+  decNumber   *dn = (decNumber *)&wide;
+  decContext   set;
+  Quad         res;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+  decContextSetRounding(&set, round);      // used by decimal128FromNumber() if the coefficient must be rounded
+
+  if ( length < 1 || length > MDQ_BCD_MAX ) {
+      decContextSetStatus(&set, DEC_Invalid_operation);
+
+      res.val = mdq_nan();
+      res.status = decContextGetStatus(&set);
+      return res;
+  }
+
+  while ( length > 1 && *bcd == 0 ) {      // decNumberSetBCD() requires the exact number of digits
+      bcd++;
+      length--;
+  }
+
+  // clamp exponent, so that the adjusted exponent stays far inside decNumber limits. It doesn't change the result, which is already Overflow or Underflow.
+
+  if ( exp > 999999 ) {
+      exp = 999999;
+  } else if ( exp < -999999 ) {
+      exp = -999999;
+  }
+
+  decNumberZero(dn);
+  dn->digits = length;                     // decNumberSetBCD() uses this field to locate the most significant unit
+  decNumberSetBCD(dn, bcd, length);
+  dn->exponent = exp;
+  if ( sign ) {
+      dn->bits |= DECNEG;
+  }
+
+  decimal128FromNumber((decimal128 *)&res.val, dn, &set);  // decQuad and decimal128 have the same layout
+  res.status = decContextGetStatus(&set) & ~(DEC_Rounded|DEC_Clamped|DEC_Subnormal);  // like other functions of this library, only Inexact is kept as informational flag
+
+  return res;
+}
+
+
+/* conversion from the BCD coefficient built by the Go parser.
+
+   The digits are passed by value, so that the Go buffer doesn't escape to the heap.
+   The coefficient is rounded with DEC_ROUND_HALF_EVEN, as by mdq_from_string().
+*/
+Quad mdq_from_parsed(Arg_BCD arg) {
+
+  return mdq_from_BCD(arg.BCD, arg.length, arg.exp, arg.sign, DEC_ROUND_HALF_EVEN);
+}
+
+
+/* conversion from a coefficient of at most 128 bits, hi * 2^64 + lo.
+
+   The coefficient is passed by value, so that no Go memory is shared with C.
+   The value is (-1)^sign * coefficient * 10^exp. If the coefficient has more than DECQUAD_Pmax digits, it is rounded with the rounding mode passed as argument, and Inexact is set.
+*/
+Quad mdq_from_uint128(uint64_t hi, uint64_t lo, int32_t exp, uint32_t sign, int round) {
+  uint32_t   limbs[4] = { (uint32_t)(hi >> 32), (uint32_t)hi, (uint32_t)(lo >> 32), (uint32_t)lo };  // most significant first
+  uint8_t    bcd[39];                                                                               // 2^128 has 39 digits
+  int32_t    length = 0;
+  uint64_t   rem;
+  uint32_t   nonzero;
+  int        i;
+
+  do {                                     // divide by 10 until the quotient is 0, the remainders are the digits
+      rem = 0;
+      nonzero = 0;
+      for ( i=0; i<4; i++ ) {
+          rem = rem << 32 | limbs[i];
+          limbs[i] = (uint32_t)(rem / 10);
+          rem %= 10;
+          nonzero |= limbs[i];
+      }
+      length++;
+      bcd[39-length] = (uint8_t)rem;
+  } while ( nonzero );
+
+  return mdq_from_BCD(bcd+39-length, length, exp, sign, round);
+}
+
+
 /************************************************************************/
 /*                        conversion to string                          */
 /************************************************************************/
@@ -545,6 +679,31 @@ Ret_str mdq_QuadToString(decQuad a) {
 }
 
 
+/* write decQuad into byte array, using engineering notation (exponent is a multiple of 3).
+
+   A terminating 0 is written in the array.
+   Never fails.
+
+   Like mdq_QuadToString, this function discards '-' sign for negative zero.
+*/
+Ret_str mdq_QuadToEngString(decQuad a) {
+
+  decQuad  a_aux; // copy of a, but with negative sign discarded if a is negative zero
+  Ret_str  res = {.length = 0};
+
+  if ( decQuadIsZero(&a) ) {
+      decQuadCopyAbs(&a_aux, &a); // discard '-' sign if any
+      decQuadToEngString(&a_aux, res.s);
+  } else {
+      decQuadToEngString(&a, res.s);
+  }
+
+  res.length = strlen(res.s);
+
+  return res;
+}
+
+
 /* write decQuad into BCD_array.
 
    The returned fields are:
@@ -667,44 +826,114 @@ Ret_int64_t mdq_to_int64(Quad a, int round) {
 /************************************************************************/
 
 
-Quad mdq_roundM(Quad a, int32_t n, int round) {
-  decContext        set;
+/* rounds a to n digits, with the rounding mode of set.
+
+   n must be in [-35..34]. Else, Invalid_operation is set in set, and NaN is returned.
+*/
+static void mdq_round_into(decQuad *res, const decQuad *a, int32_t n, decContext *set) {
   decQuad           r;
   decQuad          *operation_quantizer;
-  Quad              res;
-
-
-  decContextDefault(&set, DEC_INIT_DECQUAD);
-  set.status = a.status;
 
 
   // if n is out-of-range, return Invalid_operation
 
   if ( n > 34 || n < -35 ) {
-      decContextSetStatus(&set, DEC_Invalid_operation); // add flag to status
+      decContextSetStatus(set, DEC_Invalid_operation); // add flag to status
 
-      res.val = mdq_nan();
-      res.status = decContextGetStatus(&set);
-      return res;
+      *res = mdq_nan();
+      return;
   }
 
 
   // operation
 
-  decContextSetRounding(&set, round);                           // change rounding mode
-
   if ( n >= 0 ) {   // round or truncate fractional part
       operation_quantizer = &G_DECQUAD_QUANTIZER[n];                   // n is [0..34]
 
-      decQuadQuantize(&res.val, &a.val, operation_quantizer, &set);        // rounding, e.g. quaantize(1234.5678, 2)  --> 1234.57
+      decQuadQuantize(res, a, operation_quantizer, set);                   // rounding, e.g. quaantize(1234.5678, 2)  --> 1234.57
 
   } else {          // n < 0, round or truncate integral part
       operation_quantizer = &G_DECQUAD_INTEGRAL_PART_QUANTIZER[-n];    // -n is [0..35]
 
-      decQuadQuantize(&r, &a.val, operation_quantizer, &set);              // rounding, e.g. quaantize(1234.5678, -2) --> 12E2
-      decQuadQuantize(&res.val, &r, &G_DECQUAD_QUANTIZER[0], &set);    // right-shift the number, adding missing 0s on the left. E.g. 12E2 --> 1200E0
+      decQuadQuantize(&r, a, operation_quantizer, set);                    // rounding, e.g. quaantize(1234.5678, -2) --> 12E2
+      decQuadQuantize(res, &r, &G_DECQUAD_QUANTIZER[0], set);          // right-shift the number, adding missing 0s on the left. E.g. 12E2 --> 1200E0
+  }
+}
+
+
+Quad mdq_roundM(Quad a, int32_t n, int round) {
+  decContext        set;
+  Quad              res;
+
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+  set.status = a.status;
+  decContextSetRounding(&set, round);                           // change rounding mode
+
+  mdq_round_into(&res.val, &a.val, n, &set);
+
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/************************************************************************/
+/*                         operations on slices                         */
+/************************************************************************/
+
+/* The functions below process a whole Go slice in a single cgo call, as the cost of a cgo call is much larger than the cost of a decQuad operation.
+
+   The arrays belong to Go, and contain no Go pointer. They are not retained after the call.
+   dst can be the same array as a or b, as each element is read before the result is written.
+
+   The status of each result element is the status of its operands, and of the operation, as for the functions working on one element.
+   The functions return the union of the status of all result elements.
+*/
+
+
+/* sum of a[0..n-1], added from left to right, with DEC_ROUND_HALF_EVEN.
+   n must be > 0.
+*/
+Quad mdq_sum(const Quad *a, int64_t n) {
+  decContext  set;
+  Quad        res;
+  int64_t     i;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+  set.status = a[0].status;
+
+  res.val = a[0].val;
+
+  for ( i=1; i<n; i++ ) {
+      set.status |= a[i].status;
+      decQuadAdd(&res.val, &res.val, &a[i].val, &set);
   }
 
+  res.status = decContextGetStatus(&set);
+
+  return res;
+}
+
+
+/* sum of the products a[i]*b[i], with DEC_ROUND_HALF_EVEN.
+   Each product is added with decQuadFMA(), so that it is rounded only once, with the sum.
+   n must be > 0.
+*/
+Quad mdq_dot(const Quad *a, const Quad *b, int64_t n) {
+  decContext  set;
+  Quad        res;
+  int64_t     i;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+  set.status = a[0].status | b[0].status;
+
+  decQuadMultiply(&res.val, &a[0].val, &b[0].val, &set);
+
+  for ( i=1; i<n; i++ ) {
+      set.status |= a[i].status | b[i].status;
+      decQuadFMA(&res.val, &a[i].val, &b[i].val, &res.val, &set);
+  }
 
   res.status = decContextGetStatus(&set);
 
@@ -712,3 +941,72 @@ Quad mdq_roundM(Quad a, int32_t n, int round) {
 }
 
 
+/* dst[i] = a[i] + b[i], with DEC_ROUND_HALF_EVEN.
+*/
+uint16_t mdq_add_slices(Quad *dst, const Quad *a, const Quad *b, int64_t n) {
+  decContext  set;
+  decQuad     r;
+  uint16_t    status = 0;
+  int64_t     i;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+
+  for ( i=0; i<n; i++ ) {
+      set.status = a[i].status | b[i].status;
+      decQuadAdd(&r, &a[i].val, &b[i].val, &set);
+
+      dst[i].val = r;
+      dst[i].status = decContextGetStatus(&set);
+      status |= dst[i].status;
+  }
+
+  return status;
+}
+
+
+/* dst[i] = a[i] * s, with DEC_ROUND_HALF_EVEN.
+*/
+uint16_t mdq_mul_scalar(Quad *dst, const Quad *a, Quad s, int64_t n) {
+  decContext  set;
+  decQuad     r;
+  uint16_t    status = 0;
+  int64_t     i;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+
+  for ( i=0; i<n; i++ ) {
+      set.status = a[i].status | s.status;
+      decQuadMultiply(&r, &a[i].val, &s.val, &set);
+
+      dst[i].val = r;
+      dst[i].status = decContextGetStatus(&set);
+      status |= dst[i].status;
+  }
+
+  return status;
+}
+
+
+/* dst[i] = a[i] rounded to n digits, as mdq_roundM().
+*/
+uint16_t mdq_round_slice(Quad *dst, const Quad *a, int64_t length, int32_t n, int round) {
+  decContext  set;
+  decQuad     r;
+  uint16_t    status = 0;
+  int64_t     i;
+
+  decContextDefault(&set, DEC_INIT_DECQUAD);
+  decContextSetRounding(&set, round);                           // change rounding mode
+
+  for ( i=0; i<length; i++ ) {
+      set.status = a[i].status;
+      mdq_round_into(&r, &a[i].val, n, &set);
+
+      dst[i].val = r;
+      dst[i].status = decContextGetStatus(&set);
+      status |= dst[i].status;
+  }
+
+  return status;
+}
+
@@ -9,7 +9,6 @@ import "C"
 import (
 	"fmt"
 	"math"
-	"strconv"
 	"strings"
 	"sync"
 	"unsafe"
@@ -189,9 +188,14 @@ func (status Status) String() string {
 type QuadError Status
 
 // Error returns a string describing the error flags.
+// If there is no error flag, e.g. for QuadError(Inexact) returned by ToScaledInt64 or ToBigInt, it describes the informational flags.
 //
 func (e QuadError) Error() string {
 
+	if Status(e)&ErrorMask == 0 {
+		return fmt.Sprintf("decnum: %s", Status(e).String())
+	}
+
 	return fmt.Sprintf("decnum: %s", (Status(e) & ErrorMask).String())
 }
 
@@ -473,6 +477,22 @@ func (a Quad) Div(b Quad) Quad {
 	return Quad(C.mdq_divide(C.struct_Quad(a), C.struct_Quad(b)))
 }
 
+// MulWithMode returns a * b, rounded to 34 digits with the rounding mode passed as argument, if the exact product has more digits.
+//
+func (a Quad) MulWithMode(b Quad, rounding RoundingMode) Quad {
+
+	return Quad(C.mdq_multiplyM(C.struct_Quad(a), C.struct_Quad(b), C.int(rounding)))
+}
+
+// DivWithMode returns a/b, rounded to 34 digits with the rounding mode passed as argument, if the exact quotient has more digits.
+//
+// With Round05Up, the result can be rounded again to fewer digits with any rounding mode, and gives the same result as if the exact quotient were rounded directly.
+//
+func (a Quad) DivWithMode(b Quad, rounding RoundingMode) Quad {
+
+	return Quad(C.mdq_divideM(C.struct_Quad(a), C.struct_Quad(b), C.int(rounding)))
+}
+
 // DivInt returns the integral part of a/b.
 //
 func (a Quad) DivInt(b Quad) Quad {
@@ -574,11 +594,9 @@ func (a Quad) Abs() Quad {
 //
 func (a Quad) IsFinite() bool {
 
-	if C.mdq_is_finite(a.val) != 0 {
-		return true
-	}
+	hi, _ := a.words()
 
-	return false
+	return hi&dpdSpecialMask != dpdSpecialMask
 }
 
 /* IsInteger is discarded.
@@ -620,11 +638,9 @@ func (a Quad) IsInteger() bool {
 //
 func (a Quad) IsInfinite() bool {
 
-	if C.mdq_is_infinite(a.val) != 0 {
-		return true
-	}
+	hi, _ := a.words()
 
-	return false
+	return hi&dpdNaNMask == dpdSpecialMask
 }
 
 // IsNaN returns true if a is Nan.
@@ -634,11 +650,9 @@ func (a Quad) IsInfinite() bool {
 //
 func (a Quad) IsNaN() bool {
 
-	if C.mdq_is_nan(a.val) != 0 {
-		return true
-	}
+	hi, _ := a.words()
 
-	return false
+	return hi&dpdNaNMask == dpdNaNMask
 }
 
 // IsPositive returns true if a > 0 and not Nan.
@@ -648,11 +662,9 @@ func (a Quad) IsNaN() bool {
 //
 func (a Quad) IsPositive() bool {
 
-	if C.mdq_is_positive(a.val) != 0 {
-		return true
-	}
+	hi, lo := a.words()
 
-	return false
+	return hi>>63 == 0 && !dpdIsZero(hi, lo) && hi&dpdNaNMask != dpdNaNMask
 }
 
 // IsZero returns true if a == 0.
@@ -662,11 +674,9 @@ func (a Quad) IsPositive() bool {
 //
 func (a Quad) IsZero() bool {
 
-	if C.mdq_is_zero(a.val) != 0 {
-		return true
-	}
+	hi, lo := a.words()
 
-	return false
+	return dpdIsZero(hi, lo)
 }
 
 // IsNegative returns true if a < 0 and not NaN.
@@ -676,11 +686,9 @@ func (a Quad) IsZero() bool {
 //
 func (a Quad) IsNegative() bool {
 
-	if C.mdq_is_negative(a.val) != 0 {
-		return true
-	}
+	hi, lo := a.words()
 
-	return false
+	return hi>>63 != 0 && !dpdIsZero(hi, lo) && hi&dpdNaNMask != dpdNaNMask
 }
 
 // GetExponent returns the exponent of a.
@@ -695,7 +703,13 @@ func (a Quad) IsNegative() bool {
 //
 func (a Quad) GetExponent() int32 {
 
-	return int32(C.mdq_get_exponent(a.val))
+	hi, _ := a.words()
+
+	if hi&dpdSpecialMask == dpdSpecialMask { // Infinity or NaN, returns the top word without sign, as decQuadGetExponent()
+		return int32(hi >> 32 & 0x7e000000)
+	}
+
+	return dpdExponent(hi)
 }
 
 /************************************************************************/
@@ -704,17 +718,56 @@ func (a Quad) GetExponent() int32 {
 /*                                                                      */
 /************************************************************************/
 
+// Compare compares the values of a and b, and returns CmpLess, CmpEqual, CmpGreater, or CmpNaN if a or b is NaN.
+// -0 and 0 are equal, as are numbers with different exponents but the same value, e.g. 1.0 and 1.00.
+//
+// It gives the same result as the C function decQuadCompare, but is computed in Go without cgo call, by decoding the coefficients as integers.
+// Finite values with the same exponent, which are very common, e.g. amounts with the same number of decimals, just compare their coefficients.
+//
+// The status fields of a and b are not checked.
+// If you need to check them, you can call a.Error() and b.Error().
+//
+func (a Quad) Compare(b Quad) CmpFlag {
+
+	hiA, loA := a.words()
+	hiB, loB := b.words()
+
+	if hiA&dpdNaNMask == dpdNaNMask || hiB&dpdNaNMask == dpdNaNMask {
+		return CmpNaN
+	}
+
+	rankA := dpdRank(hiA, loA) // -2 for -Infinity, -1 for negative, 0 for zero, 1 for positive, 2 for +Infinity
+	rankB := dpdRank(hiB, loB)
+
+	cmp := 0
+
+	switch {
+	case rankA != rankB:
+		cmp = rankA - rankB
+	case rankA == 1:
+		cmp = dpdCompareMagnitude(hiA, loA, hiB, loB)
+	case rankA == -1:
+		cmp = dpdCompareMagnitude(hiB, loB, hiA, loA)
+	}
+
+	switch {
+	case cmp < 0:
+		return CmpLess
+	case cmp > 0:
+		return CmpGreater
+	default:
+		return CmpEqual
+	}
+}
+
 // Greater is true if a > b.
 //
 // The status fields of a and b are not checked.
 // If you need to check them, you can call a.Error() and b.Error().
 //
 func (a Quad) Greater(b Quad) bool {
-	var result C.uint32_t
-
-	result = C.mdq_compare(C.struct_Quad(a), C.struct_Quad(b))
 
-	if CmpFlag(result)&CmpGreater != 0 {
+	if a.Compare(b)&CmpGreater != 0 {
 		return true
 	}
 
@@ -727,11 +780,8 @@ func (a Quad) Greater(b Quad) bool {
 // If you need to check them, you can call a.Error() and b.Error().
 //
 func (a Quad) GreaterEqual(b Quad) bool {
-	var result C.uint32_t
 
-	result = C.mdq_compare(C.struct_Quad(a), C.struct_Quad(b))
-
-	if CmpFlag(result)&(CmpGreater|CmpEqual) != 0 {
+	if a.Compare(b)&(CmpGreater|CmpEqual) != 0 {
 		return true
 	}
 
@@ -744,11 +794,8 @@ func (a Quad) GreaterEqual(b Quad) bool {
 // If you need to check them, you can call a.Error() and b.Error().
 //
 func (a Quad) Equal(b Quad) bool {
-	var result C.uint32_t
-
-	result = C.mdq_compare(C.struct_Quad(a), C.struct_Quad(b))
 
-	if CmpFlag(result)&CmpEqual != 0 {
+	if a.Compare(b)&CmpEqual != 0 {
 		return true
 	}
 
@@ -761,11 +808,8 @@ func (a Quad) Equal(b Quad) bool {
 // If you need to check them, you can call a.Error() and b.Error().
 //
 func (a Quad) LessEqual(b Quad) bool {
-	var result C.uint32_t
 
-	result = C.mdq_compare(C.struct_Quad(a), C.struct_Quad(b))
-
-	if CmpFlag(result)&(CmpLess|CmpEqual) != 0 {
+	if a.Compare(b)&(CmpLess|CmpEqual) != 0 {
 		return true
 	}
 
@@ -778,11 +822,8 @@ func (a Quad) LessEqual(b Quad) bool {
 // If you need to check them, you can call a.Error() and b.Error().
 //
 func (a Quad) Less(b Quad) bool {
-	var result C.uint32_t
-
-	result = C.mdq_compare(C.struct_Quad(a), C.struct_Quad(b))
 
-	if CmpFlag(result)&CmpLess != 0 {
+	if a.Compare(b)&CmpLess != 0 {
 		return true
 	}
 
@@ -848,19 +889,21 @@ func FromInt64(value int64) Quad {
 	return Quad(C.mdq_from_int64(C.int64_t(value)))
 }
 
+// FromUint64 returns a Quad from a uint64 value.
+//
+// No error occurs.
+//
 func FromUint64(value uint64) Quad {
-	q, err := FromString(strconv.FormatUint(value, 10))
-	if err != nil {
-		panic(err)
-	}
-	return q
+
+	return FromInteger(value)
 }
 
+// FromFloat returns the Quad with the fewest digits that converts back to value, e.g. 0.1 for 0.1.
+// It is the same as FromFloat64Shortest, but NaN and Inf just return NaN and Infinity.
+//
 func FromFloat(value float64) Quad {
-	q, err := FromString(strconv.FormatFloat(value, 'f', -1, 64))
-	if err != nil {
-		panic(err)
-	}
+
+	q, _ := FromFloat64Shortest(value)
 	return q
 }
 
@@ -872,7 +915,7 @@ func FromFloat(value float64) Quad {
 
 const poolBuffCapacity = 50 // capacity of []byte buffer generated by the pool of buffers
 
-// pool is a pool of byte slice, used by AppendQuad and String.
+// pool is a pool of byte slice, used by QuadToString and ToEngString.
 //
 // note:
 //    DecquadString      = 43         sign, 34 digits, decimal point, E+xxxx, terminal \0   gives 43
@@ -884,7 +927,7 @@ const poolBuffCapacity = 50 // capacity of []byte buffer generated by the pool o
 var pool = sync.Pool{
 	New: func() interface{} {
 		//fmt.Println("---   POOL")
-		return make([]byte, poolBuffCapacity) // poolBuffCapacity is larger than DecquadString and DecquadPmax. This size is ok for AppendQuad and String methods.
+		return make([]byte, poolBuffCapacity) // poolBuffCapacity is larger than DecquadString and DecquadPmax. This size is ok for QuadToString and ToEngString methods.
 	},
 }
 
@@ -921,11 +964,45 @@ func (a Quad) QuadToString() string {
 	return s
 }
 
+// ToEngString returns the string representation of a Quad number, using engineering notation if an exponent is needed.
+// It calls the C function decQuadToEngString of the original decNumber package.
+//
+//       The exponent is always a multiple of 3, e.g. 1.5E+4 returns "15E+3", and 1.5E-7 returns "150E-9".
+//       Numbers displayed without exponent by QuadToString are displayed the same way.
+//
+// See also AppendFormat(), which writes a number in the notation passed as argument.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
+func (a Quad) ToEngString() string {
+	var (
+		retStr   C.Ret_str
+		strSlice []byte // capacity must be exactly DecquadString
+		s        string
+	)
+
+	retStr = C.mdq_QuadToEngString(a.val)
+
+	strSlice = pool.Get().([]byte)[:DecquadString]
+	defer pool.Put(strSlice)
+
+	for i := 0; i < int(retStr.length); i++ {
+		strSlice[i] = byte(retStr.s[i])
+	}
+
+	s = string(strSlice[:retStr.length])
+
+	return s
+}
+
 // AppendQuad appends string representation of Quad into byte slice.
 // AppendQuad and String are best to display Quad, as exponent notation is used less often than with QuadToString.
 //
 //       AppendQuad() writes a number without exp notation if it can be displayed with at most 34 digits, and an optional fractional point.
-//       Else, falls back on QuadToString(), which will use exponential notation.
+//       Else, writes the same string as QuadToString(), which will use exponential notation.
+//
+// The decQuad is decoded in Go, without calling C, and no memory is allocated if dst has enough capacity.
 //
 // See also method String(), which calls AppendQuad internally.
 //
@@ -934,59 +1011,27 @@ func (a Quad) QuadToString() string {
 //
 func AppendQuad(dst []byte, a Quad) []byte {
 	var (
-		retStr   C.Ret_str
-		strSlice []byte // length must be exactly DecquadString
-
-		ret             C.Ret_BCD
 		d               byte
 		skipLeadingZero bool = true
-		inf_nan         uint32
-		exp             int32
-		sign            uint32
-		BCDslice        []byte // length must be exactly DecquadPmax
+		i               int
 
 		buff [DecquadString]byte // enough for      sign    optional "0."    34 digits
 	)
 
-	// fill BCD array
+	digits, exp, signed, kind := a.decode()
 
-	ret = C.mdq_to_BCD(a.val) // sign will be 1 for negative and non-zero number, else, 0. If Inf or Nan, returns an error.
+	// if Quad value is not in 34 digits range, or Inf or Nan, we want our function to output the number, or Infinity, or NaN, as QuadToString.
 
-	BCDslice = pool.Get().([]byte)[:DecquadPmax]
-	defer pool.Put(BCDslice)
-
-	for i := 0; i < DecquadPmax; i++ {
-		BCDslice[i] = byte(ret.BCD[i])
-	}
-	inf_nan = uint32(ret.inf_nan)
-	exp = int32(ret.exp)
-	sign = uint32(ret.sign)
-
-	// if Quad value is not in 34 digits range, or Inf or Nan, we want our function to output the number, or Infinity, or NaN. Falls back on QuadToString.
-
-	if exp > 0 || exp < -DecquadPmax || inf_nan != 0 {
-		retStr = C.mdq_QuadToString(a.val) // may use exponent notation
-
-		strSlice = pool.Get().([]byte)[:DecquadString]
-		defer pool.Put(strSlice)
-
-		for i := 0; i < int(retStr.length); i++ {
-			strSlice[i] = byte(retStr.s[i])
-		}
-
-		dst = append(dst, strSlice[:retStr.length]...) // write buff into destination and return
-
-		return dst
+	if exp > 0 || exp < -DecquadPmax || kind != dpdFinite {
+		return a.appendSci(dst)
 	}
 
 	// write string. Here, the number is not Inf nor Nan.
 
-	i := 0
+	integralPartLength := len(digits) + int(exp) // here, exp is [-DecquadPmax ... 0]
 
-	integralPartLength := len(BCDslice) + int(exp) // here, exp is [-DecquadPmax ... 0]
-
-	BCDintegralPart := BCDslice[:integralPartLength]
-	BCDfractionalPart := BCDslice[integralPartLength:]
+	BCDintegralPart := digits[:integralPartLength]
+	BCDfractionalPart := digits[integralPartLength:]
 
 	for _, d = range BCDintegralPart { // ==== write integral part ====
 		if skipLeadingZero && d == 0 {
@@ -1003,8 +1048,8 @@ func AppendQuad(dst []byte, a Quad) []byte {
 		i++
 	}
 
-	if sign != 0 {
-		dst = append(dst, '-') // write '-' sign if any into destination
+	if signed && !allZero(digits[:]) {
+		dst = append(dst, '-') // write '-' sign, except for zero, into destination
 	}
 
 	dst = append(dst, buff[:i]...) // write integral part into destination
@@ -1026,6 +1071,19 @@ func AppendQuad(dst []byte, a Quad) []byte {
 	return dst
 }
 
+// allZero returns true if all digits of bcd are 0.
+//
+func allZero(bcd []byte) bool {
+
+	for _, d := range bcd {
+		if d != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
 // String is the preferred way to display a decQuad number.
 // It calls AppendQuad internally.
 //
@@ -1033,14 +1091,9 @@ func AppendQuad(dst []byte, a Quad) []byte {
 // If you need to check the status of a, you can call a.Error().
 //
 func (a Quad) String() string {
-	var buffer []byte
-
-	buffer = pool.Get().([]byte)[:0] // capacity is enough to receive result of C.mdq_QuadToString(), and also big enough to receive [sign] + [DecquadPmax digits] + [fractional dot]
-	defer pool.Put(buffer)
-
-	ss := AppendQuad(buffer[:0], a)
+	var buffer [DecquadString]byte // large enough to receive the result of AppendQuad, so that only the string is allocated
 
-	return string(ss)
+	return string(AppendQuad(buffer[:0], a))
 }
 
 /************************************************************************/
@@ -1087,43 +1140,56 @@ func (a Quad) ToInt64(rounding RoundingMode) (int64, error) {
 	return int64(result.val), nil
 }
 
-// ToFloat64 returns the float64 value from a.
+// ToFloat64 returns a rounded to the nearest float64, ties to even.
+// The conversion is direct, without string, and correctly rounded for all values, including the subnormals.
+//
+// NaN returns NaN, and Infinity returns +Inf or -Inf.
+// If a is too large for a float64, e.g. 1E+400, NaN and QuadError(InvalidOperation) are returned. If it is too small, 0 is returned.
 //
 // The status field of a is not checked.
 // If you need to check the status of a, you can call a.Error().
 //
+// It is computed in Go with fixed size integers, and it doesn't allocate memory.
+//
 func (a Quad) ToFloat64() (float64, error) {
-	var (
-		err error
-		val float64
-	)
 
-	if a.IsNaN() { // because strconv.ParseFloat doesn't parse signaling sNaN
+	switch {
+	case a.IsNaN():
 		return math.NaN(), nil
+	case a.IsInfinite():
+		return math.Inf(a.infSign()), nil
 	}
 
-	if val, err = strconv.ParseFloat(a.String(), 64); err != nil {
+	mant, exp2, neg, ok := a.toBinary(float64Format)
+	if !ok {
 		return math.NaN(), QuadError(InvalidOperation)
 	}
 
+	val := math.Ldexp(float64(mant), int(exp2)) // exact
+	if neg {
+		val = -val
+	}
+
 	return val, nil
 }
 
+// ToUint64 returns the uint64 value from a.
+// a must be an integral value, but it can have a fractional point or an exponent, e.g. 12.00 or 1.2E+3.
+//
+// If a is not integral, or is NaN or Infinity, QuadError(InvalidOperation) is returned.
+// If a is out of range, QuadError(Overflow) is returned.
+// To round a fractional value, use ToUint64WithMode.
+//
+// The status field of a is not checked.
+// If you need to check the status of a, you can call a.Error().
+//
 func (a Quad) ToUint64() (uint64, error) {
-	var (
-		err error
-		val uint64
-	)
-
-	if a.IsNaN() { // because strconv.ParseFloat doesn't parse signaling sNaN
-		return 0, QuadError(InvalidOperation)
-	}
 
-	if val, err = strconv.ParseUint(a.String(), 10, 64); err != nil {
+	if !a.IsFinite() || !a.ToIntegral(RoundDown).Equal(a) {
 		return 0, QuadError(InvalidOperation)
 	}
 
-	return val, nil
+	return a.toUnsigned(RoundDown, math.MaxUint64)
 }
 
 // Bytes returns the internal byte representation of the value field of the Quad.
@@ -1174,24 +1240,17 @@ func (a Quad) Truncate(n int32) Quad {
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts JSON strings and JSON numbers. null leaves a unchanged.
+// See JSONOptions for other behaviors.
 func (a *Quad) UnmarshalJSON(bytes []byte) error {
-	var str string
-	if len(bytes) != 0 && bytes[0] == '"' {
-		str = string(bytes[1 : len(bytes)-1])
-	} else {
-		str = string(bytes)
-	}
 
-	d, err := FromString(str)
-	*a = d
-	if err != nil {
-		return fmt.Errorf("Error decoding string '%s': %s", str, err)
-	}
-	return nil
+	return jsonQuoted.Unmarshal(bytes, a)
 }
 
 // MarshalJSON implements the json.Marshaler interface.
+// The number is written as a JSON string, e.g. "12.50".
+// See JSONNumber and JSONOptions for other behaviors.
 func (a Quad) MarshalJSON() ([]byte, error) {
-	str := "\"" + a.String() + "\""
-	return []byte(str), nil
+
+	return jsonQuoted.AppendJSON(nil, a)
 }
@@ -20,6 +20,10 @@
 #define CMP_NAN         8
 
 
+#define MDQ_BCD_MAX     800   // max number of digits accepted by mdq_from_BCD(). Enough for the exact decimal expansion of any float64.
+#define MDQ_PARSE_MAX   40    // number of digits passed by value to mdq_from_parsed(). More than DECQUAD_Pmax, for exact rounding.
+
+
 void mdq_init(void);
 
 
@@ -66,6 +70,16 @@ typedef struct Ret_int64_t {
 } Ret_int64_t;
 
 
+// struct used to pass a BCD coefficient from Go to C, by value, so that no Go memory is shared with C.
+//
+typedef struct Arg_BCD {
+  uint8_t    BCD[MDQ_PARSE_MAX];
+  int32_t    length;
+  int32_t    exp;
+  uint32_t   sign;
+} Arg_BCD;
+
+
 decQuad       mdq_zero();
 decQuad       mdq_nan();
 
@@ -75,6 +89,8 @@ Quad          mdq_add(Quad a, Quad b);
 Quad          mdq_subtract(Quad a, Quad b);
 Quad          mdq_multiply(Quad a, Quad b);
 Quad          mdq_divide(Quad a, Quad b);
+Quad          mdq_multiplyM(Quad a, Quad b, int round);
+Quad          mdq_divideM(Quad a, Quad b, int round);
 Quad          mdq_divide_integer(Quad a, Quad b);
 Quad          mdq_remainder(Quad a, Quad b);
 Quad          mdq_max(Quad a, Quad b);
@@ -97,14 +113,24 @@ uint32_t      mdq_compare(Quad a, Quad b);
 Quad          mdq_from_string(char *s);
 Quad          mdq_from_int32(int32_t value);
 Quad          mdq_from_int64(int64_t value);
+Quad          mdq_from_BCD(const uint8_t *bcd, int32_t length, int32_t exp, uint32_t sign, int round);
+Quad          mdq_from_uint128(uint64_t hi, uint64_t lo, int32_t exp, uint32_t sign, int round);
+Quad          mdq_from_parsed(Arg_BCD arg);
 
 Ret_str       mdq_QuadToString(decQuad a);
+Ret_str       mdq_QuadToEngString(decQuad a);
 Ret_BCD       mdq_to_BCD(decQuad a);
 Ret_int32_t   mdq_to_int32(Quad a, int round);
 Ret_int64_t   mdq_to_int64(Quad a, int round);
 
 Quad          mdq_roundM(Quad a, int32_t n, int round);
 
+Quad          mdq_sum(const Quad *a, int64_t n);
+Quad          mdq_dot(const Quad *a, const Quad *b, int64_t n);
+uint16_t      mdq_add_slices(Quad *dst, const Quad *a, const Quad *b, int64_t n);
+uint16_t      mdq_mul_scalar(Quad *dst, const Quad *a, Quad s, int64_t n);
+uint16_t      mdq_round_slice(Quad *dst, const Quad *a, int64_t length, int32_t n, int round);
+
 
 #endif
 
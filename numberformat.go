@@ -0,0 +1,224 @@
+package decnum
+
+/************************************************************************/
+/*                                                                      */
+/*                   locale-aware number formatting                     */
+/*                                                                      */
+/************************************************************************/
+
+// MinusStyle tells how NumberFormat writes negative numbers.
+//
+type MinusStyle int
+
+const (
+	MinusHyphen      MinusStyle = iota // -1 234,50  (ASCII hyphen-minus)
+	MinusSign                          // −1 234,50  (U+2212 MINUS SIGN)
+	MinusParentheses                   // (1 234,50) (accounting style)
+	MinusTrailing                      // 1 234,50-
+)
+
+// NumberFormat writes numbers for humans, with a group separator and a decimal separator, e.g. "1 234 567,89" or "1,234,567.89".
+//
+// The zero value writes numbers with no grouping, "." as decimal separator, and no fractional digit.
+// Predefined formats are NumberFormatRU, NumberFormatDE, NumberFormatEN, NumberFormatCH and NumberFormatIN. They can be copied and modified:
+//
+//        nf := decnum.NumberFormatDE
+//        nf.Minus = decnum.MinusParentheses
+//        s := nf.Format(q)
+//
+// NumberFormat is immutable once configured, and can be used concurrently.
+//
+type NumberFormat struct {
+	GroupSeparator   string     // separator between groups of the integral part, e.g. " " or ",". Empty means no grouping.
+	GroupSizes       []int      // sizes of the groups, from the decimal separator leftwards. The last size is repeated. nil means {3}. Indian grouping is {3, 2}.
+	DecimalSeparator string     // separator between the integral part and the fractional part. Empty means ".".
+	Minus            MinusStyle // how negative numbers are written.
+	FracDigits       int        // number of fractional digits. The number is rounded or padded with zeros. If negative, all the digits of the number are written.
+}
+
+// Predefined number formats, with 2 fractional digits.
+//
+// NumberFormatRU uses U+00A0 NO-BREAK SPACE as group separator, so that a number is never split across lines.
+// Set GroupSeparator to " " to use a plain space.
+//
+var (
+	NumberFormatRU = NumberFormat{GroupSeparator: "\u00a0", DecimalSeparator: ",", FracDigits: 2}                     // 1 234 567,89
+	NumberFormatDE = NumberFormat{GroupSeparator: ".", DecimalSeparator: ",", FracDigits: 2}                          // 1.234.567,89
+	NumberFormatEN = NumberFormat{GroupSeparator: ",", DecimalSeparator: ".", FracDigits: 2}                          // 1,234,567.89
+	NumberFormatCH = NumberFormat{GroupSeparator: "'", DecimalSeparator: ".", FracDigits: 2}                          // 1'234'567.89
+	NumberFormatIN = NumberFormat{GroupSeparator: ",", GroupSizes: []int{3, 2}, DecimalSeparator: ".", FracDigits: 2} // 12,34,567.89
+)
+
+// Append appends the representation of a to dst.
+//
+// It is built on AppendQuad: if FracDigits is not negative, a is first rounded with RoundHalfEven mode, then it is written by AppendQuad,
+// and the text is rewritten with the separators, the minus style and the fractional digits of nf.
+// To use another rounding mode, round a beforehand, e.g. with a.RoundWithMode(2, RoundHalfUp).
+//
+// The number is never written with an exponent: if AppendQuad uses one, e.g. for 1E+40, it is expanded, and 1E+40 is written with all its zeros.
+// If the rounded number is zero, no minus sign is written, e.g. -0.001 is written "0,00" with 2 fractional digits.
+//
+// NaN is written "NaN", and Infinity is written "Infinity", with the minus style if negative.
+//
+// The status field of a is not checked.
+//
+func (nf NumberFormat) Append(dst []byte, a Quad) []byte {
+	var (
+		buff  [DecquadString]byte
+		plain [DecquadString]byte
+		body  []byte
+	)
+
+	if a.IsNaN() {
+		return AppendQuad(dst, a)
+	}
+
+	if nf.FracDigits >= 0 && a.IsFinite() {
+		a = a.roundToExp(clampExp(-int64(nf.FracDigits)), RoundHalfEven)
+	}
+
+	body = AppendQuad(buff[:0], a) // no sign for zero
+
+	neg := body[0] == '-'
+	if neg {
+		body = body[1:]
+	}
+
+	if a.IsFinite() {
+		body = appendPlainText(plain[:0], body, nf.FracDigits)
+	}
+
+	if neg {
+		switch nf.Minus {
+		case MinusSign:
+			dst = append(dst, "\u2212"...)
+		case MinusParentheses:
+			dst = append(dst, '(')
+		case MinusTrailing:
+		default:
+			dst = append(dst, '-')
+		}
+	}
+
+	dst = nf.appendGrouped(dst, body)
+
+	if neg {
+		switch nf.Minus {
+		case MinusParentheses:
+			dst = append(dst, ')')
+		case MinusTrailing:
+			dst = append(dst, '-')
+		}
+	}
+
+	return dst
+}
+
+// Format returns the representation of a. See Append.
+//
+func (nf NumberFormat) Format(a Quad) string {
+	var buff [DecquadString]byte
+
+	return string(nf.Append(buff[:0], a))
+}
+
+// appendPlainText appends the number written by AppendQuad in text, without sign, in plain notation, with frac fractional digits.
+// If frac is negative, all the digits are written. The number must have been rounded to frac digits before.
+//
+//        "1.5E+3"       becomes "1500"
+//        "1.5"          becomes "1.50" for frac 2
+//
+func appendPlainText(dst []byte, text []byte, frac int) []byte {
+	var buff [DecquadPmax]byte
+
+	exp := 0
+
+	for i, c := range text {
+		if c == 'E' { // e.g. 1.5E+3
+			for _, d := range text[i+2:] {
+				exp = exp*10 + int(d-'0')
+			}
+			if text[i+1] == '-' {
+				exp = -exp
+			}
+			text = text[:i]
+			break
+		}
+	}
+
+	digits := buff[:0] // coefficient, without the decimal point and leading zeros
+	for i, c := range text {
+		switch {
+		case c == '.':
+			exp -= len(text) - i - 1
+		case c != '0' || len(digits) > 0 || i == len(text)-1: // the last 0 is kept, e.g. for "0.00"
+			digits = append(digits, c)
+		}
+	}
+
+	if frac < 0 {
+		frac = 0
+		if exp < 0 {
+			frac = -exp
+		}
+	}
+
+	return appendPlain(dst, digits, exp, frac)
+}
+
+// appendGrouped appends body, a plain number as written by appendPlainText, with the group and decimal separators of nf.
+//
+func (nf NumberFormat) appendGrouped(dst []byte, body []byte) []byte {
+
+	intLen := 0
+	for intLen < len(body) && body[intLen] != '.' {
+		intLen++
+	}
+
+	intPart := body[:intLen]
+
+	if nf.GroupSeparator == "" || intPart[0] < '0' || intPart[0] > '9' { // no grouping, or Infinity
+		dst = append(dst, intPart...)
+	} else {
+		sizes := nf.GroupSizes
+		if len(sizes) == 0 {
+			sizes = defaultGroupSizes
+		}
+
+		groupSize := func(i int) int { // size of the i-th group from the right
+			if i < len(sizes) {
+				return sizes[i]
+			}
+			return sizes[len(sizes)-1]
+		}
+
+		// count the groups from the right, then write them from the left
+
+		n, grouped := 0, 0
+		for groupSize(n) > 0 && grouped+groupSize(n) < intLen {
+			grouped += groupSize(n)
+			n++
+		}
+
+		pos := intLen - grouped
+		dst = append(dst, intPart[:pos]...)
+		for i := n - 1; i >= 0; i-- {
+			dst = append(dst, nf.GroupSeparator...)
+			dst = append(dst, intPart[pos:pos+groupSize(i)]...)
+			pos += groupSize(i)
+		}
+	}
+
+	if intLen < len(body) {
+		if nf.DecimalSeparator == "" {
+			dst = append(dst, '.')
+		} else {
+			dst = append(dst, nf.DecimalSeparator...)
+		}
+		dst = append(dst, body[intLen+1:]...)
+	}
+
+	return dst
+}
+
+var defaultGroupSizes = []int{3}
@@ -0,0 +1,84 @@
+package decnum
+
+import (
+	"testing"
+)
+
+func TestNumberFormat(t *testing.T) {
+
+	ru := NumberFormatRU
+	ru.GroupSeparator = " "
+
+	accounting := NumberFormatEN
+	accounting.Minus = MinusParentheses
+
+	trailing := NumberFormatDE
+	trailing.Minus = MinusTrailing
+
+	sign := NumberFormatCH
+	sign.Minus = MinusSign
+
+	all := NumberFormatEN
+	all.FracDigits = -1
+
+	integral := NumberFormat{GroupSeparator: " "}
+
+	samples := []struct {
+		nf       NumberFormat
+		a        string
+		expected string
+	}{
+		{NumberFormatRU, "1234567.891", "1\u00a0234\u00a0567,89"},
+		{ru, "1234567.891", "1 234 567,89"},
+		{ru, "-1234.5", "-1 234,50"},
+		{ru, "123", "123,00"},
+		{ru, "1000", "1 000,00"},
+		{ru, "0.005", "0,00"},
+		{ru, "0.015", "0,02"},
+		{ru, "-0.001", "0,00"},
+		{NumberFormatDE, "1234567.89", "1.234.567,89"},
+		{NumberFormatDE, "-999.999", "-1.000,00"},
+		{NumberFormatEN, "1234567.89", "1,234,567.89"},
+		{NumberFormatEN, "1E+6", "1,000,000.00"},
+		{NumberFormatEN, "12345678901234567890123.456", "12,345,678,901,234,567,890,123.46"},
+		{NumberFormatCH, "1234567.89", "1'234'567.89"},
+		{NumberFormatIN, "1234567.89", "12,34,567.89"},
+		{NumberFormatIN, "123456789", "12,34,56,789.00"},
+		{NumberFormatIN, "1234", "1,234.00"},
+		{NumberFormatIN, "123", "123.00"},
+		{accounting, "-1234.5", "(1,234.50)"},
+		{accounting, "1234.5", "1,234.50"},
+		{trailing, "-1234.5", "1.234,50-"},
+		{sign, "-1234.5", "−1'234.50"},
+		{all, "1234.5678", "1,234.5678"},
+		{all, "1234", "1,234"},
+		{all, "1.2E+5", "120,000"},
+		{all, "1.2E-8", "0.000000012"},
+		{all, "1.5E-40", "0.00000000000000000000000000000000000000015"}, // AppendQuad writes 1.5E-40
+		{all, "1E+40", "10,000,000,000,000,000,000,000,000,000,000,000,000,000"},
+		{all, "0E+3", "0"},
+		{all, "-0.00", "0.00"},
+		{NumberFormatEN, "-1.5E+36", "-1,500,000,000,000,000,000,000,000,000,000,000,000.00"},
+		{integral, "1234567.5", "1 234 568"},
+		{integral, "1234566.5", "1 234 566"},
+		{NumberFormat{}, "1234.5", "1234"},
+		{NumberFormat{FracDigits: 1, DecimalSeparator: ","}, "1234.56", "1234,6"},
+		{NumberFormat{GroupSeparator: ".", GroupSizes: []int{4}, FracDigits: -1}, "123456789", "1.2345.6789"},
+		{NumberFormat{GroupSeparator: ",", GroupSizes: []int{3, 0}, FracDigits: -1}, "123456789", "123456,789"},
+		{accounting, "-Inf", "(Infinity)"},
+		{NumberFormatRU, "Inf", "Infinity"},
+		{NumberFormatRU, "NaN", "NaN"},
+	}
+
+	for _, s := range samples {
+		r := s.nf.Format(must_quad(s.a))
+		if r != s.expected {
+			t.Fatalf("%+v.Format(%s) = %q, expected %q", s.nf, s.a, r, s.expected)
+		}
+	}
+
+	b := NumberFormatEN.Append([]byte("total: "), must_quad("1234.5"))
+	if string(b) != "total: 1,234.50" {
+		t.Fatalf("Append must append to dst, got %q", b)
+	}
+}
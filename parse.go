@@ -0,0 +1,185 @@
+package decnum
+
+/*
+
+#include "mydecquad.h"
+*/
+import "C"
+
+import (
+	"bytes"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                        parsing from []byte                           */
+/*                                                                      */
+/************************************************************************/
+
+const parseMax = C.MDQ_PARSE_MAX // number of digits kept by Parse, the last one being a sticky digit
+
+// Parse returns a Quad from a byte slice, e.g. a field of a CSV or JSON document.
+// It accepts the same syntax as FromString, and returns the same result.
+//
+// Unlike FromString, it doesn't allocate memory, neither in Go nor in C: the number is parsed in Go,
+// and its coefficient is passed by value to C.
+// Only the rare NaN with payload, sNaN and invalid strings starting with a letter are passed to FromString.
+//
+// If the coefficient has more than 34 digits, it is rounded with RoundHalfEven mode, and Inexact is set.
+//
+// This function returns result.Error() as a convenience.
+//
+func Parse(b []byte) (result Quad, err error) {
+	var (
+		arg     C.Arg_BCD
+		n       int   // number of digits stored in arg.BCD
+		dropped int64 // number of digits beyond parseMax-1
+		sticky  bool  // true if a dropped digit is not 0
+		frac    int64 // number of fractional digits
+		exp     int64
+		seen    bool // true if at least one digit is present in the coefficient
+		dot     bool
+		neg     bool
+	)
+
+	b = bytes.TrimSpace(b)
+
+	i := 0
+	if i < len(b) && (b[i] == '+' || b[i] == '-') {
+		neg = b[i] == '-'
+		i++
+	}
+
+	// coefficient
+
+	for ; i < len(b); i++ {
+		c := b[i]
+
+		if c == '.' && !dot {
+			dot = true
+			continue
+		}
+
+		if c < '0' || c > '9' {
+			break
+		}
+
+		seen = true
+		if dot {
+			frac++
+		}
+
+		switch {
+		case n == 0 && c == '0': // leading zero
+		case n < parseMax-1:
+			arg.BCD[n] = C.uint8_t(c - '0')
+			n++
+		default:
+			dropped++
+			sticky = sticky || c != '0'
+		}
+	}
+
+	if !seen {
+		return parseSpecial(b[i:], neg, b)
+	}
+
+	// exponent
+
+	if i < len(b) {
+		if b[i] != 'e' && b[i] != 'E' {
+			return parseSyntaxError()
+		}
+		i++
+
+		expNeg := false
+		if i < len(b) && (b[i] == '+' || b[i] == '-') {
+			expNeg = b[i] == '-'
+			i++
+		}
+
+		if i == len(b) {
+			return parseSyntaxError()
+		}
+
+		for ; i < len(b); i++ {
+			c := b[i]
+			if c < '0' || c > '9' {
+				return parseSyntaxError()
+			}
+			if exp < 1e12 { // saturate, any larger exponent is out of range
+				exp = exp*10 + int64(c-'0')
+			}
+		}
+
+		if expNeg {
+			exp = -exp
+		}
+	}
+
+	// the dropped digits are replaced by a single sticky digit. The rounding position (34th digit) is before it, so the rounding is exact.
+
+	if sticky {
+		arg.BCD[n] = 1
+		n++
+		dropped--
+	}
+
+	if n == 0 {
+		n = 1 // arg.BCD[0] is 0
+	}
+
+	arg.length = C.int32_t(n)
+	arg.exp = C.int32_t(clampExp(exp - frac + dropped))
+	if neg {
+		arg.sign = 1
+	}
+
+	result = Quad(C.mdq_from_parsed(arg))
+
+	return result, result.Error()
+}
+
+// parseSpecial returns Infinity or NaN for a string without digits. rest is the string after the sign, and b is the whole string.
+// Inf, Infinity and NaN are parsed directly. Other strings are passed to FromString, which parses sNaN, NaN with payload, and returns the syntax errors.
+//
+func parseSpecial(rest []byte, neg bool, b []byte) (Quad, error) {
+
+	switch {
+	case equalFoldASCII(rest, "inf") || equalFoldASCII(rest, "infinity"):
+		if neg {
+			return g_infinity.Neg(), nil
+		}
+		return g_infinity, nil
+	case equalFoldASCII(rest, "nan") && !neg:
+		return NaN(), nil
+	}
+
+	return FromString(string(b))
+}
+
+// parseSyntaxError returns NaN with ConversionSyntax status, as FromString.
+//
+func parseSyntaxError() (Quad, error) {
+
+	result := NaN().SetStatusFlags(ConversionSyntax)
+
+	return result, result.Error()
+}
+
+// equalFoldASCII returns true if b is equal to the lowercase ASCII string s, ignoring case.
+//
+func equalFoldASCII(b []byte, s string) bool {
+
+	if len(b) != len(s) {
+		return false
+	}
+
+	for i := range b {
+		if b[i]|0x20 != s[i] {
+			return false
+		}
+	}
+
+	return true
+}
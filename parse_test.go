@@ -0,0 +1,103 @@
+package decnum
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+
+	samples := []string{
+		"0", "-0", "+0", "0.000", "-0.00E-5", "00012.3400", ".5", "5.", "-.5e3",
+		"123.456", "  123.456\t\n", "1E+6111", "1E+6144", "1E+6145", "1E-6176", "1E-6177", "5E-6177", "9.99E+6144",
+		"1e99999999999999999999", "1e-99999999999999999999", "0e99999999999999999999",
+		"1234567890123456789012345678901234", "12345678901234567890123456789012345", "12345678901234567890123456789012355",
+		"1234567890123456789012345678901234500000000000000000000001", "1234567890123456789012345678901234500000000000000000000000",
+		"-99999999999999999999999999999999999999999999999999999999.9999", "0.000000000000000000000000000000000000000000000000000001",
+		"0.00000000000000000000000000000000000000000000000000000123456789012345678901234567890123456789",
+		"Inf", "-Infinity", "INF", "+inf", "NaN", "nan", "-NaN", "sNaN", "NaN123",
+		"", "-", ".", "+.", "1e", "1e+", "1ee5", "1.2.3", "12a", "abc", "1 2", "--1", "0x10", "1_000",
+	}
+
+	for _, s := range samples {
+		r, err := Parse([]byte(s))
+		expected, eerr := FromString(s)
+
+		if r.String() != expected.String() || r.Status()&(ErrorMask|Inexact) != expected.Status()&(ErrorMask|Inexact) || (err == nil) != (eerr == nil) {
+			t.Fatalf("Parse(%q) = %s, status %s, %v, expected %s, status %s, %v", s, r, r.Status(), err, expected, expected.Status(), eerr)
+		}
+	}
+}
+
+func TestParseRandom(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(5))
+	chars := "0123456789"
+
+	for i := 0; i < 20000; i++ {
+		b := make([]byte, 0, 80)
+
+		if rnd.Intn(3) == 0 {
+			b = append(b, "+-"[rnd.Intn(2)])
+		}
+
+		n := 1 + rnd.Intn(60)
+		dot := rnd.Intn(n + 1)
+		for j := 0; j < n; j++ {
+			if j == dot && rnd.Intn(2) == 0 {
+				b = append(b, '.')
+			}
+			if rnd.Intn(4) == 0 {
+				b = append(b, '0')
+			} else {
+				b = append(b, chars[rnd.Intn(10)])
+			}
+		}
+
+		if rnd.Intn(2) == 0 {
+			b = append(b, 'E')
+			b = strconv.AppendInt(b, int64(rnd.Intn(14000)-7000), 10)
+		}
+
+		r, _ := Parse(b)
+		expected, _ := FromString(string(b))
+
+		if r.String() != expected.String() || r.Status()&(ErrorMask|Inexact) != expected.Status()&(ErrorMask|Inexact) {
+			t.Fatalf("Parse(%q) = %s, status %s, expected %s, status %s", b, r, r.Status(), expected, expected.Status())
+		}
+	}
+}
+
+func TestParseAllocs(t *testing.T) {
+
+	inputs := [][]byte{[]byte("12345.6789"), []byte("-1234567890123456789012345678901234567890E-20"), []byte(" Infinity "), []byte("NaN")}
+
+	n := testing.AllocsPerRun(100, func() {
+		for _, b := range inputs {
+			Parse(b)
+		}
+	})
+
+	if n != 0 {
+		t.Fatalf("Parse allocates %v times, expected 0", n)
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+
+	s := []byte("12345.6789")
+
+	for i := 0; i < b.N; i++ {
+		Parse(s)
+	}
+}
+
+func BenchmarkFromString(b *testing.B) {
+
+	s := "12345.6789"
+
+	for i := 0; i < b.N; i++ {
+		FromString(s)
+	}
+}
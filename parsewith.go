@@ -0,0 +1,373 @@
+package decnum
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                locale-aware and accounting-style parsing             */
+/*                                                                      */
+/************************************************************************/
+
+// ParseOptions configures ParseWith, to parse numbers typed by humans, e.g. "1 234,50", "(123.45)", "12.5%" or "$1,000".
+//
+// The zero value accepts the same finite numbers as FromString, e.g. "-1234.50" or "1.5E+3", without NaN and Infinity.
+// Predefined options are ParseRU, ParseDE and ParseEN. They can be copied and modified:
+//
+//        opts := decnum.ParseEN
+//        opts.CurrencySymbols = []string{"$", "USD"}
+//        q, err := decnum.ParseWith("$1,234.50", opts)
+//
+type ParseOptions struct {
+	GroupSeparators  string   // characters accepted as group separator between digits of the integral part, e.g. " \u00a0" or ",". Empty means no grouping.
+	GroupSizes       []int    // sizes of the groups, from the decimal separator leftwards. The last size is repeated. nil means {3}. Indian grouping is {3, 2}.
+	DecimalSeparator rune     // decimal separator. 0 means '.'.
+	Underscores      bool     // if true, "_" is accepted between digits, e.g. "1_000_000".
+	Parentheses      bool     // if true, a number enclosed in parentheses is negative, e.g. "(123.45)" is -123.45.
+	Percent          bool     // if true, a trailing "%" divides the number by 100, e.g. "12.5%" is 0.125.
+	CurrencySymbols  []string // currency symbols accepted before or after the number, e.g. "$", "€" or "руб.". Case-sensitive.
+}
+
+// Predefined parse options, matching NumberFormatRU, NumberFormatDE and NumberFormatEN.
+//
+var (
+	ParseRU = ParseOptions{GroupSeparators: " \u00a0\u202f", DecimalSeparator: ',', Parentheses: true, Percent: true} // 1 234 567,89
+	ParseDE = ParseOptions{GroupSeparators: ".", DecimalSeparator: ',', Parentheses: true, Percent: true}             // 1.234.567,89
+	ParseEN = ParseOptions{GroupSeparators: ",", DecimalSeparator: '.', Parentheses: true, Percent: true}             // 1,234,567.89
+)
+
+// ParseError is returned by ParseWith if the string is not a valid number.
+// errors.Is(err, QuadError(ConversionSyntax)) is true for a ParseError.
+//
+type ParseError struct {
+	Input  string // the string passed to ParseWith
+	Offset int    // byte offset of the first invalid character in Input, or len(Input) if the string ends unexpectedly
+	Msg    string // description of the error
+}
+
+// Error returns a string describing the error and its offset.
+//
+func (e *ParseError) Error() string {
+
+	return fmt.Sprintf("decnum: cannot parse %q: %s at offset %d", e.Input, e.Msg, e.Offset)
+}
+
+// Unwrap returns QuadError(ConversionSyntax).
+//
+func (e *ParseError) Unwrap() error {
+
+	return QuadError(ConversionSyntax)
+}
+
+// ParseWith parses a number written by a human, with the separators, signs and symbols configured in opts.
+//
+// The accepted syntax is, with optional parts in brackets, and spaces allowed between the parts:
+//
+//        [(] [sign] [currency] [sign] digits [decimal-separator digits] [exponent] [currency] [%] [sign] [)]
+//
+// Group separators and underscores are only accepted between two digits of the integral part.
+// If there is a group separator, the groups must have the sizes given by opts.GroupSizes, except the leftmost one, which can be shorter,
+// e.g. "1,234,567" is accepted by ParseEN, but "1,23,4" is not. The underscores don't delimit groups.
+// Only one sign is accepted, and no sign is accepted in parentheses. The minus sign can be "-" or "−" (U+2212).
+// Only one currency symbol is accepted, before or after the digits.
+// The exponent is "e" or "E", followed by an optional sign and digits, e.g. "1,5E-3" with DecimalSeparator ','.
+//
+// The number is exact: no rounding occurs unless it has more than 34 digits, in which case it is rounded as by FromString.
+//
+// If s is not valid, the result is NaN with ConversionSyntax status, and the error is a *ParseError, which gives the byte offset of the first error.
+// Else, the error is result.Error().
+//
+func ParseWith(s string, opts ParseOptions) (result Quad, err error) {
+	var (
+		buff      [DecquadString + 16]byte
+		num       = buff[:0] // significant digits, then exponent
+		kept      bool
+		dropped   int   // number of digits of the integral part dropped, as num is full
+		frac      int   // number of fractional digits not dropped
+		exp       int64 // explicit exponent
+		neg       bool
+		hasSign   bool
+		hasCurr   bool
+		hasParen  bool
+		hasDigits bool
+		hasGroups bool
+	)
+
+	decSep := opts.DecimalSeparator
+	if decSep == 0 {
+		decSep = '.'
+	}
+
+	fail := func(pos int, msg string) (Quad, error) {
+		return NaN().SetStatusFlags(ConversionSyntax), &ParseError{Input: s, Offset: pos, Msg: msg}
+	}
+
+	pos := skipSpaces(s, 0)
+
+	if opts.Parentheses && pos < len(s) && s[pos] == '(' {
+		hasParen = true
+		pos = skipSpaces(s, pos+1)
+	}
+
+	// sign and currency before the digits, in any order
+
+	for i := 0; i < 2; i++ {
+		if n, minus := parseSign(s[pos:]); n > 0 && !hasSign {
+			if hasParen {
+				return fail(pos, "sign in parentheses")
+			}
+			hasSign, neg = true, minus
+			pos = skipSpaces(s, pos+n)
+		} else if n = matchCurrency(s[pos:], opts.CurrencySymbols); n > 0 && !hasCurr {
+			hasCurr = true
+			pos = skipSpaces(s, pos+n)
+		}
+	}
+
+	// integral part
+
+	intStart := pos
+
+	for pos < len(s) {
+		c, size := utf8.DecodeRuneInString(s[pos:])
+
+		if c >= '0' && c <= '9' {
+			if num, kept = appendDigit(num, byte(c)); !kept {
+				dropped++
+			}
+			hasDigits = true
+			pos++
+			continue
+		}
+
+		if c != decSep && (c == '_' && opts.Underscores || strings.ContainsRune(opts.GroupSeparators, c)) {
+			if !hasDigits || pos+size >= len(s) || s[pos+size] < '0' || s[pos+size] > '9' {
+				if unicode.IsSpace(c) { // space before a currency symbol, e.g. "1234 руб."
+					break
+				}
+				return fail(pos, "misplaced group separator")
+			}
+			hasGroups = hasGroups || c != '_'
+			pos += size
+			continue
+		}
+
+		break
+	}
+
+	if hasGroups {
+		if errPos := checkGroups(s[intStart:pos], opts); errPos >= 0 {
+			return fail(intStart+errPos, "wrong group size")
+		}
+	}
+
+	// fractional part
+
+	if c, size := utf8.DecodeRuneInString(s[pos:]); pos < len(s) && c == decSep {
+		pos += size
+		for pos < len(s) {
+			c, size = utf8.DecodeRuneInString(s[pos:])
+			if c >= '0' && c <= '9' {
+				if num, kept = appendDigit(num, byte(c)); kept {
+					frac++
+				}
+				hasDigits = true
+				pos++
+				continue
+			}
+			if c == '_' && opts.Underscores && s[pos-1] >= '0' && s[pos-1] <= '9' && pos+1 < len(s) && s[pos+1] >= '0' && s[pos+1] <= '9' {
+				pos++
+				continue
+			}
+			break
+		}
+	}
+
+	if !hasDigits {
+		return fail(pos, "digit expected")
+	}
+
+	// exponent
+
+	if n := matchCurrency(s[pos:], opts.CurrencySymbols); n == 0 && pos < len(s) && (s[pos] == 'e' || s[pos] == 'E') { // "1EUR" is not an exponent
+		start := pos + 1
+		end := start
+		if end < len(s) && (s[end] == '+' || s[end] == '-') {
+			end++
+		}
+		for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+			end++
+		}
+		var errExp error
+		if exp, errExp = strconv.ParseInt(s[start:end], 10, 32); errExp != nil {
+			return fail(start, "invalid exponent")
+		}
+		pos = end
+	}
+
+	pos = skipSpaces(s, pos)
+
+	// currency, percent and sign after the digits
+
+	if n := matchCurrency(s[pos:], opts.CurrencySymbols); n > 0 && !hasCurr {
+		hasCurr = true
+		pos = skipSpaces(s, pos+n)
+	}
+
+	if opts.Percent && pos < len(s) && s[pos] == '%' {
+		exp -= 2
+		pos = skipSpaces(s, pos+1)
+	}
+
+	if n, minus := parseSign(s[pos:]); n > 0 {
+		if hasSign {
+			return fail(pos, "duplicate sign")
+		}
+		if hasParen {
+			return fail(pos, "sign in parentheses")
+		}
+		neg = minus
+		pos = skipSpaces(s, pos+n)
+	}
+
+	if hasParen {
+		if pos >= len(s) || s[pos] != ')' {
+			return fail(pos, "')' expected")
+		}
+		neg = true
+		pos = skipSpaces(s, pos+1)
+	}
+
+	if pos < len(s) {
+		return fail(pos, "unexpected character")
+	}
+
+	// build the string for FromString, e.g. "-12345E-2"
+
+	if len(num) == 0 { // only zeros
+		num = append(num, '0')
+	}
+
+	num = append(num, 'E')
+	num = strconv.AppendInt(num, exp-int64(frac)+int64(dropped), 10)
+
+	if neg {
+		result, err = FromString("-" + string(num))
+	} else {
+		result, err = FromString(string(num))
+	}
+
+	return result, err
+}
+
+// checkGroups checks the sizes of the groups of digits in intPart, the integral part of a number, which contains group separators.
+// The groups are checked from the right, as they are defined by opts.GroupSizes.
+//
+// It returns -1 if the sizes are correct, else the offset in intPart of the first digit of the first wrong group from the right,
+// or of the first separator if the leftmost group is too long.
+//
+func checkGroups(intPart string, opts ParseOptions) int {
+
+	sizes := opts.GroupSizes
+	if len(sizes) == 0 {
+		sizes = defaultGroupSizes
+	}
+
+	groupSize := func(i int) int { // size of the i-th group from the right
+		if i < len(sizes) {
+			return sizes[i]
+		}
+		return sizes[len(sizes)-1]
+	}
+
+	group, digits := 0, 0
+	end := len(intPart) // offset following the current group, that is, of its separator on the right
+
+	for pos := len(intPart); pos > 0; {
+		c, size := utf8.DecodeLastRuneInString(intPart[:pos])
+		pos -= size
+
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case c != '_': // group separator
+			if digits != groupSize(group) {
+				return pos + size
+			}
+			group, digits = group+1, 0
+			end = pos
+		}
+	}
+
+	if digits > groupSize(group) {
+		return end
+	}
+
+	return -1
+}
+
+// appendDigit appends the digit c to num, which contains the significant digits of a number.
+// Leading zeros are skipped. If num is full, c is dropped, and kept is false.
+//
+// num can hold more digits than DecquadPmax, so that a dropped digit is never needed to round the number.
+// But a non-zero dropped digit sets the last digit of num to a non-zero value, so that rounding is correct at a tie, e.g. 0.5000...001.
+//
+func appendDigit(num []byte, c byte) (_ []byte, kept bool) {
+
+	switch {
+	case len(num) == 0 && c == '0':
+		return num, true
+	case len(num) < DecquadString:
+		return append(num, c), true
+	case c != '0':
+		num[len(num)-1] |= 1 // '0' becomes '1', other digits stay non-zero
+	}
+	return num, false
+}
+
+// skipSpaces returns the position of the first non-space character of s at or after pos.
+//
+func skipSpaces(s string, pos int) int {
+
+	for pos < len(s) {
+		c, size := utf8.DecodeRuneInString(s[pos:])
+		if !unicode.IsSpace(c) {
+			break
+		}
+		pos += size
+	}
+	return pos
+}
+
+// parseSign returns the length of the sign at the beginning of s, or 0 if none, and true if it is a minus sign.
+//
+func parseSign(s string) (n int, minus bool) {
+
+	switch {
+	case strings.HasPrefix(s, "-"):
+		return 1, true
+	case strings.HasPrefix(s, "+"):
+		return 1, false
+	case strings.HasPrefix(s, "\u2212"):
+		return len("\u2212"), true
+	}
+	return 0, false
+}
+
+// matchCurrency returns the length of the longest currency symbol at the beginning of s, or 0 if none.
+//
+func matchCurrency(s string, symbols []string) int {
+	var n int
+
+	for _, symbol := range symbols {
+		if len(symbol) > n && strings.HasPrefix(s, symbol) {
+			n = len(symbol)
+		}
+	}
+	return n
+}
@@ -0,0 +1,146 @@
+package decnum
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseWith(t *testing.T) {
+
+	usd := ParseEN
+	usd.CurrencySymbols = []string{"$", "USD", "US$"}
+
+	rub := ParseRU
+	rub.CurrencySymbols = []string{"₽", "руб.", "р."}
+
+	eur := ParseDE
+	eur.CurrencySymbols = []string{"€", "EUR"}
+
+	under := ParseOptions{Underscores: true}
+
+	samples := []struct {
+		s        string
+		opts     ParseOptions
+		expected string
+	}{
+		{"1234.50", ParseOptions{}, "1234.50"},
+		{"-1.5E+3", ParseOptions{}, "-1.5E+3"},
+		{"  +12  ", ParseOptions{}, "12"},
+		{"0.00", ParseOptions{}, "0.00"},
+		{"-0", ParseOptions{}, "0"},
+		{".5", ParseOptions{}, "0.5"},
+		{"5.", ParseOptions{}, "5"},
+		{"1 234,50", ParseRU, "1234.50"},
+		{"1 234 567,89", ParseRU, "1234567.89"},
+		{"1 234,5", ParseRU, "1234.5"},
+		{"−1 234,50", ParseRU, "-1234.50"},
+		{"1.234.567,89", ParseDE, "1234567.89"},
+		{"1,234,567.89", ParseEN, "1234567.89"},
+		{"(123.45)", ParseEN, "-123.45"},
+		{"( 1,000 )", ParseEN, "-1000"},
+		{"12.5%", ParseEN, "0.125"},
+		{"-3 %", ParseEN, "-0.03"},
+		{"1,5%", ParseRU, "0.015"},
+		{"$1,000", usd, "1000"},
+		{"-$1,000.25", usd, "-1000.25"},
+		{"$-1,000.25", usd, "-1000.25"},
+		{"($1,000.25)", usd, "-1000.25"},
+		{"1000 USD", usd, "1000"},
+		{"US$ 5", usd, "5"},
+		{"1 234,50 руб.", rub, "1234.50"},
+		{"1234 ₽", rub, "1234"},
+		{"1.234,50 €", eur, "1234.50"},
+		{"1,5EUR", eur, "1.5"},
+		{"1,5E3", eur, "1.5E+3"},
+		{"1.234,50-", eur, "-1234.50"},
+		{"1_000_000", under, "1000000"},
+		{"1_000.000_1", under, "1000.0001"},
+		{"1,0_00", ParseOptions{GroupSeparators: ",", Underscores: true}, "1000"},
+		{"12,34,567.89", ParseOptions{GroupSeparators: ",", GroupSizes: []int{3, 2}}, "1234567.89"},
+		{"1,234.5", ParseOptions{GroupSeparators: ",", GroupSizes: []int{3, 2}}, "1234.5"},
+		{"1234567890123456789012345678901234", ParseOptions{}, "1234567890123456789012345678901234"},
+		{"12,345,678,901,234,567,890,123,456,789,012,345", ParseEN, "1.234567890123456789012345678901234E+34"},
+		{"12345678901234567890123456789012345", ParseOptions{}, "1.234567890123456789012345678901234E+34"},
+		{"12345678901234567890123456789012345000000000000000000000000000000000000000000000", ParseOptions{}, "1.234567890123456789012345678901234E+79"},
+		{"0.00000000000000000000000000000000000000000000000000000000000000000001", ParseOptions{}, "1E-68"},
+		{"1.23456789012345678901234567890123450000000000000000000000000000000000000000001", ParseOptions{}, "1.234567890123456789012345678901235"},
+		{"1.23456789012345678901234567890123450000000000000000000000000000000000000000000", ParseOptions{}, "1.234567890123456789012345678901234"},
+	}
+
+	for _, s := range samples {
+		r, err := ParseWith(s.s, s.opts)
+		if err != nil {
+			t.Fatalf("ParseWith(%q) returned error %v", s.s, err)
+		}
+		if r.String() != s.expected {
+			t.Fatalf("ParseWith(%q) = %s, expected %s", s.s, r, s.expected)
+		}
+	}
+}
+
+func TestParseWithError(t *testing.T) {
+
+	usd := ParseEN
+	usd.CurrencySymbols = []string{"$"}
+
+	samples := []struct {
+		s      string
+		opts   ParseOptions
+		offset int
+	}{
+		{"", ParseOptions{}, 0},
+		{"   ", ParseOptions{}, 3},
+		{"abc", ParseOptions{}, 0},
+		{"12a", ParseOptions{}, 2},
+		{"1,000", ParseOptions{}, 1},
+		{"NaN", ParseOptions{}, 0},
+		{"Inf", ParseOptions{}, 0},
+		{"1 234", ParseOptions{}, 2},
+		{",100", ParseEN, 0},
+		{"1,,000", ParseEN, 1},
+		{"1,000,", ParseEN, 5},
+		{"1.000,5", ParseEN, 5},
+		{"1,23,4", ParseEN, 5},
+		{"1,23", ParseEN, 2},
+		{"1,2345", ParseEN, 2},
+		{"1234,567", ParseEN, 4},
+		{"1.23,45", ParseEN, 4},
+		{"1 2345,6", ParseRU, 2},
+		{"1,234,567", ParseOptions{GroupSeparators: ",", GroupSizes: []int{3, 2}}, 2},
+		{"(123", ParseEN, 4},
+		{"(-123)", ParseEN, 1},
+		{"(123)-", ParseEN, 5},
+		{"123)", ParseEN, 3},
+		{"-5-", ParseEN, 2},
+		{"--5", ParseEN, 1},
+		{"5%", ParseOptions{}, 1},
+		{"$5$", usd, 2},
+		{"€5", usd, 0},
+		{"1E", ParseOptions{}, 2},
+		{"1E+", ParseOptions{}, 2},
+		{"1E99999999999", ParseOptions{}, 2},
+		{"1__0", ParseOptions{Underscores: true}, 1},
+		{"1_", ParseOptions{Underscores: true}, 1},
+		{"1._5", ParseOptions{Underscores: true}, 2},
+	}
+
+	for _, s := range samples {
+		r, err := ParseWith(s.s, s.opts)
+		if err == nil {
+			t.Fatalf("ParseWith(%q) = %s, expected error", s.s, r)
+		}
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Fatalf("ParseWith(%q) returned %T, expected *ParseError", s.s, err)
+		}
+		if pe.Offset != s.offset {
+			t.Fatalf("ParseWith(%q) error %q at offset %d, expected %d", s.s, err, pe.Offset, s.offset)
+		}
+		if !errors.Is(err, QuadError(ConversionSyntax)) {
+			t.Fatalf("ParseWith(%q) error must wrap ConversionSyntax", s.s)
+		}
+		if !r.IsNaN() || r.Status()&ConversionSyntax == 0 {
+			t.Fatalf("ParseWith(%q) = %s with status %s, expected NaN with ConversionSyntax", s.s, r, r.Status())
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package decnum
+
+/************************************************************************/
+/*                                                                      */
+/*                        rounding to an increment                      */
+/*                                                                      */
+/************************************************************************/
+
+// RoundToIncrement rounds a to a multiple of inc, with the rounding mode passed as argument.
+// inc can be any positive decimal number, e.g. 0.05 for Swiss cash rounding, 0.25, 0.009 or 50.
+//
+//        1.03.RoundToIncrement(0.05, RoundHalfUp)      returns 1.05
+//        1.02.RoundToIncrement(0.05, RoundHalfUp)      returns 1.00
+//        1234.RoundToIncrement(50, RoundHalfUp)        returns 1250
+//
+// The rounding mode is applied to the quotient a/inc, which is rounded to an integer n, and the result is n*inc.
+// So, "even" for RoundHalfEven means an even multiple of inc, e.g. 1.025 rounded to 0.05 with RoundHalfEven is 1.00 (20 * 0.05),
+// and Round05Up rounds away from zero only if the last digit of the truncated quotient is 0 or 5.
+//
+// Rounding is exact: the quotient is computed with Round05Up, so that its rounding to an integer is the same as for the exact quotient.
+// The result has the exponent of inc, e.g. 1.05 for inc 0.05, so it is displayed with the same number of fractional digits.
+//
+// If the result is not equal to a, Inexact flag is set.
+// If inc is not a finite positive number, or if the result needs more than 34 digits with the exponent of inc, InvalidOperation flag is set and NaN is returned.
+// If a is NaN or Infinity, it is returned unchanged.
+//
+func (a Quad) RoundToIncrement(inc Quad, rounding RoundingMode) Quad {
+
+	if !a.IsFinite() {
+		return a
+	}
+
+	if !inc.IsFinite() || !inc.IsPositive() {
+		return NaN().SetStatusFlags(Status(a.status) | InvalidOperation)
+	}
+
+	r := a.ClearStatus().DivWithMode(inc.ClearStatus(), Round05Up).ToIntegral(rounding).Mul(inc)
+
+	r = r.Quantize(inc, RoundHalfEven) // exact, sets the exponent of inc, e.g. for 0 or 2E+1. Fails if the result needs more than 34 digits.
+	if r.IsNaN() {
+		return NaN().SetStatusFlags(Status(a.status) | InvalidOperation)
+	}
+
+	r = r.ClearStatus().SetStatusFlags(Status(a.status) | Status(inc.status))
+
+	if !r.Equal(a) {
+		r = r.SetStatusFlags(Inexact)
+	}
+
+	return r
+}
+
+/************************************************************************/
+/*                                                                      */
+/*                     significant digits rounding                      */
+/*                                                                      */
+/************************************************************************/
+
+// Precision returns the number of significant digits in the coefficient of a, that is, the number of digits without leading zeros.
+//
+//        Precision(123.45)     returns 5
+//        Precision(1.50)       returns 3        trailing zeros are significant
+//        Precision(1.23E+40)   returns 3
+//        Precision(0.000)      returns 1
+//
+// It returns 0 if a is NaN or Infinity.
+//
+func (a Quad) Precision() int {
+
+	if !a.IsFinite() {
+		return 0
+	}
+
+	bcd, _, _, _ := a.toBCD()
+
+	i := 0
+	for i < DecquadPmax-1 && bcd[i] == 0 {
+		i++
+	}
+
+	return DecquadPmax - i
+}
+
+// RoundSig rounds a to n significant digits, with the rounding mode passed as argument, regardless of the magnitude of a.
+//
+//        RoundSig(123.456, 3)      returns 123
+//        RoundSig(0.00123456, 3)   returns 0.00123
+//        RoundSig(1.23456E+40, 3)  returns 1.23E+40
+//        RoundSig(999.5, 3)        returns 1.00E+3 with RoundHalfUp
+//        RoundSig(1.5, 3)          returns 1.5      a is never padded with zeros
+//
+// Rounding is exact, as it uses Quantize.
+//
+// If n is not positive, InvalidOperation flag is set and NaN is returned.
+// If a is zero, NaN or Infinity, or has no more than n digits, it is returned unchanged.
+//
+func (a Quad) RoundSig(n int, rounding RoundingMode) Quad {
+
+	if n <= 0 {
+		return NaN().SetStatusFlags(Status(a.status) | InvalidOperation)
+	}
+
+	if !a.IsFinite() || a.IsZero() || a.Precision() <= n {
+		return a
+	}
+
+	adjusted := int64(a.GetExponent()) + int64(a.Precision()) - 1 // exponent of the most significant digit
+
+	r := a.roundToExp(clampExp(adjusted-int64(n)+1), rounding)
+
+	if r.Precision() > n { // carry, e.g. 999.5 rounded to 1000, with a trailing zero that can be removed exactly
+		r = r.roundToExp(clampExp(adjusted-int64(n)+2), rounding)
+	}
+
+	return r
+}
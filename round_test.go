@@ -0,0 +1,129 @@
+package decnum
+
+import (
+	"testing"
+)
+
+func TestRoundToIncrement(t *testing.T) {
+
+	modes := []RoundingMode{RoundCeiling, RoundDown, RoundFloor, RoundHalfDown, RoundHalfEven, RoundHalfUp, RoundUp, Round05Up}
+
+	samples := []struct {
+		a        string
+		inc      string
+		expected [8]string // in the order of modes
+	}{
+		{"1.025", "0.05", [8]string{"1.05", "1.00", "1.00", "1.00", "1.00", "1.05", "1.05", "1.05"}},
+		{"1.03", "0.05", [8]string{"1.05", "1.00", "1.00", "1.05", "1.05", "1.05", "1.05", "1.05"}},
+		{"1.02", "0.05", [8]string{"1.05", "1.00", "1.00", "1.00", "1.00", "1.00", "1.05", "1.05"}},
+		{"1.075", "0.05", [8]string{"1.10", "1.05", "1.05", "1.05", "1.10", "1.10", "1.10", "1.05"}},
+		{"1.08", "0.05", [8]string{"1.10", "1.05", "1.05", "1.10", "1.10", "1.10", "1.10", "1.05"}},
+		{"-1.025", "0.05", [8]string{"-1.00", "-1.00", "-1.05", "-1.00", "-1.00", "-1.05", "-1.05", "-1.05"}},
+		{"1.05", "0.05", [8]string{"1.05", "1.05", "1.05", "1.05", "1.05", "1.05", "1.05", "1.05"}},
+		{"3.13", "0.25", [8]string{"3.25", "3.00", "3.00", "3.25", "3.25", "3.25", "3.25", "3.00"}},
+		{"3.125", "0.25", [8]string{"3.25", "3.00", "3.00", "3.00", "3.00", "3.25", "3.25", "3.00"}},
+		{"1234", "50", [8]string{"1250", "1200", "1200", "1250", "1250", "1250", "1250", "1200"}},
+		{"1225", "50", [8]string{"1250", "1200", "1200", "1200", "1200", "1250", "1250", "1200"}},
+		{"1010", "50", [8]string{"1050", "1000", "1000", "1000", "1000", "1000", "1050", "1050"}},
+		{"1.2345", "0.009", [8]string{"1.242", "1.233", "1.233", "1.233", "1.233", "1.233", "1.242", "1.233"}},
+		{"0", "0.05", [8]string{"0.00", "0.00", "0.00", "0.00", "0.00", "0.00", "0.00", "0.00"}},
+		{"0.001", "0.05", [8]string{"0.05", "0.00", "0.00", "0.00", "0.00", "0.00", "0.05", "0.05"}},
+		{"1E+3", "50", [8]string{"1000", "1000", "1000", "1000", "1000", "1000", "1000", "1000"}},
+		{"Inf", "0.05", [8]string{"Infinity", "Infinity", "Infinity", "Infinity", "Infinity", "Infinity", "Infinity", "Infinity"}},
+	}
+
+	for _, s := range samples {
+		for i, mode := range modes {
+			a := must_quad(s.a)
+			r := a.RoundToIncrement(must_quad(s.inc), mode)
+			if r.String() != s.expected[i] {
+				t.Fatalf("%s.RoundToIncrement(%s, %s) = %s, expected %s", s.a, s.inc, mode, r, s.expected[i])
+			}
+			if r.Error() != nil {
+				t.Fatalf("%s.RoundToIncrement(%s, %s) has error %v", s.a, s.inc, mode, r.Error())
+			}
+			if inexact := r.Status()&Inexact != 0; r.IsFinite() && inexact == r.Equal(a) {
+				t.Fatalf("%s.RoundToIncrement(%s, %s) = %s, Inexact flag is %v", s.a, s.inc, mode, r, inexact)
+			}
+		}
+	}
+
+	for _, inc := range []string{"0", "-0.05", "NaN", "Inf"} {
+		r := must_quad("1.03").RoundToIncrement(must_quad(inc), RoundHalfUp)
+		if !r.IsNaN() || r.Status()&InvalidOperation == 0 {
+			t.Fatalf("1.03.RoundToIncrement(%s) = %s, expected NaN with InvalidOperation", inc, r)
+		}
+	}
+
+	r := must_quad("1E+40").RoundToIncrement(must_quad("0.05"), RoundHalfUp)
+	if !r.IsNaN() || r.Status()&InvalidOperation == 0 {
+		t.Fatalf("1E+40.RoundToIncrement(0.05) = %s, expected NaN with InvalidOperation", r)
+	}
+}
+
+func TestPrecision(t *testing.T) {
+
+	samples := []struct {
+		a         string
+		precision int
+	}{
+		{"123.45", 5},
+		{"1.50", 3},
+		{"1.23E+40", 3},
+		{"0.000", 1},
+		{"0", 1},
+		{"-7", 1},
+		{"0.00012", 2},
+		{"1234567890123456789012345678901234", 34},
+		{"NaN", 0},
+		{"-Inf", 0},
+	}
+
+	for _, s := range samples {
+		if p := must_quad(s.a).Precision(); p != s.precision {
+			t.Fatalf("%s.Precision() = %d, expected %d", s.a, p, s.precision)
+		}
+	}
+}
+
+func TestRoundSig(t *testing.T) {
+
+	samples := []struct {
+		a        string
+		n        int
+		rounding RoundingMode
+		expected string
+	}{
+		{"123.456", 3, RoundHalfEven, "123"},
+		{"123.456", 4, RoundHalfEven, "123.5"},
+		{"123.456", 1, RoundHalfEven, "1E+2"},
+		{"0.00123456", 3, RoundHalfEven, "0.00123"},
+		{"1.23456E+40", 3, RoundHalfEven, "1.23E+40"},
+		{"1.23556E+40", 3, RoundDown, "1.23E+40"},
+		{"1.23556E+40", 3, RoundHalfUp, "1.24E+40"},
+		{"-1.23556E+40", 3, RoundFloor, "-1.24E+40"},
+		{"999.5", 3, RoundHalfUp, "1.00E+3"},
+		{"999.5", 3, RoundHalfEven, "1.00E+3"},
+		{"998.5", 3, RoundHalfEven, "998"},
+		{"1.5", 3, RoundHalfEven, "1.5"},
+		{"1.50", 2, RoundHalfEven, "1.5"},
+		{"0", 3, RoundHalfEven, "0"},
+		{"1234567890123456789012345678901234", 30, RoundHalfUp, "1.23456789012345678901234567890E+33"},
+		{"NaN", 3, RoundHalfEven, "NaN"},
+		{"Inf", 3, RoundHalfEven, "Infinity"},
+	}
+
+	for _, s := range samples {
+		r := must_quad(s.a).RoundSig(s.n, s.rounding)
+		if r.String() != s.expected {
+			t.Fatalf("%s.RoundSig(%d, %s) = %s, expected %s", s.a, s.n, s.rounding, r, s.expected)
+		}
+		if r.IsFinite() && r.Precision() > s.n {
+			t.Fatalf("%s.RoundSig(%d, %s) = %s has %d digits", s.a, s.n, s.rounding, r, r.Precision())
+		}
+	}
+
+	if r := must_quad("1.5").RoundSig(0, RoundHalfEven); !r.IsNaN() || r.Status()&InvalidOperation == 0 {
+		t.Fatalf("RoundSig(0) = %s, expected NaN with InvalidOperation", r)
+	}
+}
@@ -0,0 +1,64 @@
+package decnum
+
+/************************************************************************/
+/*                                                                      */
+/*                  scaled integers, e.g. amounts in cents              */
+/*                                                                      */
+/************************************************************************/
+
+// FromScaledInt64 returns v / 10^scale, e.g. FromScaledInt64(12345, 2) is 123.45.
+// It is exact, and the exponent of the result is -scale, so that 100 cents are 1.00.
+//
+func FromScaledInt64(v int64, scale int32) Quad {
+
+	return New(v, clampExp(-int64(scale)))
+}
+
+// ToScaledInt64 returns a * 10^scale as an int64, e.g. 123.45 with scale 2 returns 12345.
+// It is the inverse of FromScaledInt64.
+//
+// If a has more than scale fractional digits, it is rounded exactly with the rounding mode passed as argument,
+// and the rounded value is returned with QuadError(Inexact) as error. Use errors.Is(err, QuadError(Inexact)) to accept it.
+//
+// If a is NaN or Infinity, 0 and QuadError(InvalidOperation) are returned.
+// If the result doesn't fit in an int64, 0 and QuadError(Overflow) are returned.
+//
+// The status field of a is not checked.
+//
+func (a Quad) ToScaledInt64(scale int32, rounding RoundingMode) (int64, error) {
+	var u uint64
+
+	if !a.IsFinite() {
+		return 0, QuadError(InvalidOperation)
+	}
+
+	q := a.ClearStatus().Quantize(unitQuad(clampExp(-int64(scale))), rounding) // exact rounding. Fails if the result needs more than 34 digits, far too large for an int64.
+	if q.IsNaN() {
+		return 0, QuadError(Overflow)
+	}
+
+	bcd, _, neg, _ := q.toBCD()
+
+	limit := uint64(1<<63 - 1)
+	if neg {
+		limit = 1 << 63
+	}
+
+	for _, d := range bcd {
+		if u > (limit-uint64(d))/10 {
+			return 0, QuadError(Overflow)
+		}
+		u = u*10 + uint64(d)
+	}
+
+	v := int64(u)
+	if neg {
+		v = -v // also correct for math.MinInt64
+	}
+
+	if q.Status()&Inexact != 0 {
+		return v, QuadError(Inexact)
+	}
+
+	return v, nil
+}
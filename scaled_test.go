@@ -0,0 +1,79 @@
+package decnum
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFromScaledInt64(t *testing.T) {
+
+	samples := []struct {
+		v        int64
+		scale    int32
+		expected string
+	}{
+		{12345, 2, "123.45"},
+		{100, 2, "1.00"},
+		{-5, 2, "-0.05"},
+		{0, 2, "0.00"},
+		{123, 0, "123"},
+		{123, -2, "1.23E+4"},
+		{math.MinInt64, 2, "-92233720368547758.08"},
+	}
+
+	for _, s := range samples {
+		if r := FromScaledInt64(s.v, s.scale); r.String() != s.expected || r.Status() != 0 {
+			t.Fatalf("FromScaledInt64(%d, %d) = %s, status %s, expected %s", s.v, s.scale, r, r.Status(), s.expected)
+		}
+	}
+}
+
+func TestToScaledInt64(t *testing.T) {
+
+	samples := []struct {
+		a        string
+		scale    int32
+		rounding RoundingMode
+		expected int64
+		err      error
+	}{
+		{"123.45", 2, RoundHalfEven, 12345, nil},
+		{"1", 2, RoundHalfEven, 100, nil},
+		{"-0.05", 2, RoundHalfEven, -5, nil},
+		{"1.2E+4", 2, RoundHalfEven, 1200000, nil},
+		{"123.455", 2, RoundHalfEven, 12346, QuadError(Inexact)},
+		{"123.445", 2, RoundHalfEven, 12344, QuadError(Inexact)},
+		{"123.445", 2, RoundHalfUp, 12345, QuadError(Inexact)},
+		{"-123.445", 2, RoundFloor, -12345, QuadError(Inexact)},
+		{"123.449", 2, RoundDown, 12344, QuadError(Inexact)},
+		{"1234", -2, RoundHalfUp, 12, QuadError(Inexact)},
+		{"92233720368547758.07", 2, RoundHalfEven, math.MaxInt64, nil},
+		{"-92233720368547758.08", 2, RoundHalfEven, math.MinInt64, nil},
+		{"92233720368547758.08", 2, RoundHalfEven, 0, QuadError(Overflow)},
+		{"-92233720368547758.09", 2, RoundHalfEven, 0, QuadError(Overflow)},
+		{"1E+30", 2, RoundHalfEven, 0, QuadError(Overflow)},
+		{"1", 40, RoundHalfEven, 0, QuadError(Overflow)},
+		{"NaN", 2, RoundHalfEven, 0, QuadError(InvalidOperation)},
+		{"-Inf", 2, RoundHalfEven, 0, QuadError(InvalidOperation)},
+	}
+
+	for _, s := range samples {
+		v, err := must_quad(s.a).ToScaledInt64(s.scale, s.rounding)
+		if v != s.expected || err != s.err {
+			t.Fatalf("%s.ToScaledInt64(%d, %s) = %d, %v, expected %d, %v", s.a, s.scale, s.rounding, v, err, s.expected, s.err)
+		}
+	}
+
+	_, err := must_quad("1.005").ToScaledInt64(2, RoundHalfUp)
+	if !errors.Is(err, QuadError(Inexact)) || err.Error() != "decnum: Inexact" {
+		t.Fatalf("inexact error = %v", err)
+	}
+
+	for _, v := range []int64{0, 1, -1, 12345, math.MaxInt64, math.MinInt64} {
+		r, err := FromScaledInt64(v, 4).ToScaledInt64(4, RoundHalfEven)
+		if r != v || err != nil {
+			t.Fatalf("FromScaledInt64(%d, 4).ToScaledInt64(4) = %d, %v", v, r, err)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package decnum
+
+/*
+#include "mydecquad.h"
+*/
+import "C"
+
+import (
+	"unsafe"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                         operations on slices                         */
+/*                                                                      */
+/************************************************************************/
+
+// The functions below process a whole slice in a single cgo call, which is much faster than calling the operation for each element,
+// as the cost of a cgo call is larger than the cost of the operation itself.
+//
+// The status of each result element is the same as if the operation had been called for this element, e.g. dst[i] has the same status as a[i].Add(b[i]).
+// The Status returned is the union of the status of all result elements. An error can be obtained with QuadError(status & ErrorMask).
+//
+// dst can be the same slice as a or b, for in-place operations.
+
+// quadPtr returns a pointer to the first element of a, which must not be empty, for passing the array to C.
+// Quad contains no Go pointer, so that the array can be passed to C.
+//
+func quadPtr(a []Quad) *C.Quad {
+
+	return (*C.Quad)(unsafe.Pointer(&a[0]))
+}
+
+// Sum returns the sum of the elements of a, added from left to right, as a[0].Add(a[1]).Add(a[2])...
+// The sum of an empty slice is 0.
+//
+func Sum(a []Quad) Quad {
+
+	if len(a) == 0 {
+		return Zero()
+	}
+
+	return Quad(C.mdq_sum(quadPtr(a), C.int64_t(len(a))))
+}
+
+// Dot returns the sum of the products a[i]*b[i].
+//
+// Each product is added with a fused multiply-add, so that it is rounded only once, with the sum. So, if a rounding is needed, the result can be more accurate
+// than the sum of a[i].Mul(b[i]).
+//
+// The dot product of empty slices is 0.
+// If a and b have different lengths, InvalidOperation flag is set and NaN is returned.
+//
+func Dot(a []Quad, b []Quad) Quad {
+
+	switch {
+	case len(a) != len(b):
+		return NaN().SetStatusFlags(InvalidOperation)
+	case len(a) == 0:
+		return Zero()
+	}
+
+	return Quad(C.mdq_dot(quadPtr(a), quadPtr(b), C.int64_t(len(a))))
+}
+
+// AddSlices writes a[i] + b[i] into dst[i].
+//
+// If dst, a and b don't have the same length, InvalidOperation is returned and dst is not modified.
+//
+func AddSlices(dst []Quad, a []Quad, b []Quad) Status {
+
+	switch {
+	case len(dst) != len(a) || len(dst) != len(b):
+		return InvalidOperation
+	case len(dst) == 0:
+		return 0
+	}
+
+	return Status(C.mdq_add_slices(quadPtr(dst), quadPtr(a), quadPtr(b), C.int64_t(len(dst))))
+}
+
+// MulScalar writes a[i] * s into dst[i].
+//
+// If dst and a don't have the same length, InvalidOperation is returned and dst is not modified.
+//
+func MulScalar(dst []Quad, a []Quad, s Quad) Status {
+
+	switch {
+	case len(dst) != len(a):
+		return InvalidOperation
+	case len(dst) == 0:
+		return 0
+	}
+
+	return Status(C.mdq_mul_scalar(quadPtr(dst), quadPtr(a), C.struct_Quad(s), C.int64_t(len(dst))))
+}
+
+// RoundSlice writes a[i].RoundWithMode(n, rounding) into dst[i].
+//
+//  n must be in the range [-35...34]. Else, Invalid Operation flag is set, and all elements of dst are NaN.
+//
+// If dst and a don't have the same length, InvalidOperation is returned and dst is not modified.
+//
+func RoundSlice(dst []Quad, a []Quad, n int32, rounding RoundingMode) Status {
+
+	switch {
+	case len(dst) != len(a):
+		return InvalidOperation
+	case len(dst) == 0:
+		return 0
+	}
+
+	return Status(C.mdq_round_slice(quadPtr(dst), quadPtr(a), C.int64_t(len(dst)), C.int32_t(n), C.int(rounding)))
+}
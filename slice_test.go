@@ -0,0 +1,277 @@
+package decnum
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// randomQuads returns n random Quads, with various exponents, and some special values and status flags.
+func randomQuads(r *rand.Rand, n int) []Quad {
+
+	specials := []Quad{must_quad("NaN"), must_quad("-Infinity"), must_quad("9.999999999999999999999999999999999E+6144"), must_quad("1E-6170"), Zero().SetStatusFlags(Inexact)}
+
+	a := make([]Quad, n)
+	for i := range a {
+		switch r.Intn(20) {
+		case 0:
+			a[i] = specials[r.Intn(len(specials))]
+		case 1:
+			a[i] = New(r.Int63(), int32(r.Intn(40)-20)).Mul(New(r.Int63(), 0)) // rounded, with Inexact
+		default:
+			a[i] = New(r.Int63n(2000000)-1000000, int32(r.Intn(5)-4))
+		}
+	}
+
+	return a
+}
+
+func sameQuad(a Quad, b Quad) bool {
+
+	return a.QuadToString() == b.QuadToString() && a.Status() == b.Status()
+}
+
+func TestSum(t *testing.T) {
+
+	r := rand.New(rand.NewSource(49))
+
+	if s := Sum(nil); !sameQuad(s, Zero()) {
+		t.Fatalf("Sum(nil) = %s, expected 0", s)
+	}
+
+	for i := 0; i < 1000; i++ {
+		a := randomQuads(r, r.Intn(20)+1)
+
+		expected := a[0]
+		for _, x := range a[1:] {
+			expected = expected.Add(x)
+		}
+
+		if s := Sum(a); !sameQuad(s, expected) {
+			t.Fatalf("Sum(%v) = %s %s, expected %s %s", a, s, s.Status(), expected, expected.Status())
+		}
+	}
+}
+
+func TestDot(t *testing.T) {
+
+	samples := []struct {
+		a        string
+		b        string
+		expected string
+	}{
+		{"", "", "0"},
+		{"1.5", "2", "3.0"},
+		{"1.5 2.25 -3", "2 4 0.5", "10.50"},
+		{"1E+6144 1", "10 1", "Infinity"},
+		{"1 NaN", "1 1", "NaN"},
+		{"1 2", "1", "NaN"},
+		{"3333333333333333333333333333333333 1", "3 -1", "9999999999999999999999999999999998"}, // 9999999999999999999999999999999999 - 1 is exact with fused multiply-add
+	}
+
+	for _, s := range samples {
+		var a, b []Quad
+		for _, f := range strings.Fields(s.a) {
+			a = append(a, must_quad(f))
+		}
+		for _, f := range strings.Fields(s.b) {
+			b = append(b, must_quad(f))
+		}
+
+		if r := Dot(a, b); r.String() != s.expected {
+			t.Fatalf("Dot([%s], [%s]) = %s, expected %s", s.a, s.b, r, s.expected)
+		}
+	}
+}
+
+func TestSliceOperations(t *testing.T) {
+
+	r := rand.New(rand.NewSource(49))
+
+	for i := 0; i < 1000; i++ {
+		n := r.Intn(20)
+		a, b := randomQuads(r, n), randomQuads(r, n)
+		s := randomQuads(r, 1)[0]
+		digits := int32(r.Intn(40) - 36) // sometimes out of range
+		rounding := []RoundingMode{RoundCeiling, RoundDown, RoundFloor, RoundHalfDown, RoundHalfEven, RoundHalfUp, RoundUp, Round05Up}[r.Intn(8)]
+
+		dst := make([]Quad, n)
+		var expected Status
+
+		check := func(name string, status Status, f func(i int) Quad) {
+			var union Status
+
+			for i := range dst {
+				e := f(i)
+				if !sameQuad(dst[i], e) {
+					t.Fatalf("%s: element %d is %s %s, expected %s %s", name, i, dst[i], dst[i].Status(), e, e.Status())
+				}
+				union |= e.Status()
+			}
+
+			if status != union {
+				t.Fatalf("%s returns status %s, expected %s", name, status, union)
+			}
+		}
+
+		expected = AddSlices(dst, a, b)
+		check("AddSlices", expected, func(i int) Quad { return a[i].Add(b[i]) })
+
+		expected = MulScalar(dst, a, s)
+		check("MulScalar", expected, func(i int) Quad { return a[i].Mul(s) })
+
+		expected = RoundSlice(dst, a, digits, rounding)
+		check("RoundSlice", expected, func(i int) Quad { return a[i].RoundWithMode(digits, rounding) })
+
+		original := append([]Quad(nil), a...) // in place
+		expected = AddSlices(a, a, b)
+		dst = a
+		check("AddSlices in place", expected, func(i int) Quad { return original[i].Add(b[i]) })
+	}
+}
+
+func TestSliceLengths(t *testing.T) {
+
+	a := []Quad{One(), One()}
+	dst := []Quad{Zero()}
+
+	if s := AddSlices(dst, a, a); s != InvalidOperation || !dst[0].IsZero() {
+		t.Fatalf("AddSlices with different lengths returns %s", s)
+	}
+
+	if s := MulScalar(dst, a, One()); s != InvalidOperation || !dst[0].IsZero() {
+		t.Fatalf("MulScalar with different lengths returns %s", s)
+	}
+
+	if s := RoundSlice(dst, a, 2, RoundHalfEven); s != InvalidOperation || !dst[0].IsZero() {
+		t.Fatalf("RoundSlice with different lengths returns %s", s)
+	}
+
+	if s := AddSlices(nil, nil, nil); s != 0 {
+		t.Fatalf("AddSlices with empty slices returns %s", s)
+	}
+}
+
+// benchmarks, comparing the slice operations with the loops calling the operation for each element.
+
+const benchmarkSliceLength = 1000
+
+func benchmarkAmounts() []Quad {
+
+	r := rand.New(rand.NewSource(1))
+
+	a := make([]Quad, benchmarkSliceLength)
+	for i := range a {
+		a[i] = New(r.Int63n(10000000), -2)
+	}
+
+	return a
+}
+
+func BenchmarkSum(b *testing.B) {
+
+	a := benchmarkAmounts()
+
+	for i := 0; i < b.N; i++ {
+		Sum(a)
+	}
+}
+
+func BenchmarkSumLoop(b *testing.B) {
+
+	a := benchmarkAmounts()
+
+	for i := 0; i < b.N; i++ {
+		s := Zero()
+		for _, x := range a {
+			s = s.Add(x)
+		}
+	}
+}
+
+func BenchmarkDot(b *testing.B) {
+
+	a, c := benchmarkAmounts(), benchmarkAmounts()
+
+	for i := 0; i < b.N; i++ {
+		Dot(a, c)
+	}
+}
+
+func BenchmarkDotLoop(b *testing.B) {
+
+	a, c := benchmarkAmounts(), benchmarkAmounts()
+
+	for i := 0; i < b.N; i++ {
+		s := Zero()
+		for j := range a {
+			s = s.Add(a[j].Mul(c[j]))
+		}
+	}
+}
+
+func BenchmarkAddSlices(b *testing.B) {
+
+	a, c := benchmarkAmounts(), benchmarkAmounts()
+	dst := make([]Quad, len(a))
+
+	for i := 0; i < b.N; i++ {
+		AddSlices(dst, a, c)
+	}
+}
+
+func BenchmarkAddSlicesLoop(b *testing.B) {
+
+	a, c := benchmarkAmounts(), benchmarkAmounts()
+	dst := make([]Quad, len(a))
+
+	for i := 0; i < b.N; i++ {
+		for j := range dst {
+			dst[j] = a[j].Add(c[j])
+		}
+	}
+}
+
+func BenchmarkMulScalar(b *testing.B) {
+
+	a, s := benchmarkAmounts(), must_quad("1.2")
+	dst := make([]Quad, len(a))
+
+	for i := 0; i < b.N; i++ {
+		MulScalar(dst, a, s)
+	}
+}
+
+func BenchmarkMulScalarLoop(b *testing.B) {
+
+	a, s := benchmarkAmounts(), must_quad("1.2")
+	dst := make([]Quad, len(a))
+
+	for i := 0; i < b.N; i++ {
+		for j := range dst {
+			dst[j] = a[j].Mul(s)
+		}
+	}
+}
+
+func BenchmarkRoundSlice(b *testing.B) {
+
+	a := benchmarkAmounts()
+	dst := make([]Quad, len(a))
+
+	for i := 0; i < b.N; i++ {
+		RoundSlice(dst, a, 1, RoundHalfEven)
+	}
+}
+
+func BenchmarkRoundSliceLoop(b *testing.B) {
+
+	a := benchmarkAmounts()
+	dst := make([]Quad, len(a))
+
+	for i := 0; i < b.N; i++ {
+		for j := range dst {
+			dst[j] = a[j].RoundWithMode(1, RoundHalfEven)
+		}
+	}
+}
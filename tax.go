@@ -0,0 +1,62 @@
+package decnum
+
+/************************************************************************/
+/*                                                                      */
+/*                  tax-inclusive and tax-exclusive amounts             */
+/*                                                                      */
+/************************************************************************/
+
+// In the functions below, rate is a percentage, e.g. 20 for 20% VAT, and the tax is rounded to scale fractional digits with the rounding mode passed as argument.
+//
+// The intermediate products and quotients are computed with Round05Up, so that the final rounding gives the same result as if the exact tax were rounded directly,
+// as long as the amount multiplied by rate has no more than 34 digits.
+//
+// The amounts always balance exactly: gross == net + tax.
+//
+// The status returned contains the flags of the arguments and of all the operations, e.g. Inexact if the tax has been rounded.
+// If rate is -100, ExtractTax and NetFromGross divide by 0: for a gross amount other than 0, the tax is NaN, and DivisionByZero and InvalidOperation are set.
+// An error can be obtained with QuadError(status & ErrorMask).
+
+var hundred = FromInt32(100)
+
+// AddTax returns the tax on net, and the gross amount net + tax.
+//
+//        tax = net * rate / 100, rounded
+//
+//        AddTax(100.00, 20, 2, RoundHalfUp)     returns 120.00, 20.00
+//        AddTax(0.99, 20, 2, RoundHalfUp)       returns 1.19, 0.20             exact tax is 0.198
+//
+func AddTax(net Quad, rate Quad, scale int32, rounding RoundingMode) (gross Quad, tax Quad, status Status) {
+
+	tax = net.MulWithMode(rate, Round05Up).DivWithMode(hundred, Round05Up).Quantize(unitQuad(clampExp(-int64(scale))), rounding)
+	gross = net.Add(tax)
+
+	return gross, tax, gross.Status()
+}
+
+// ExtractTax returns the tax included in the gross amount.
+//
+//        tax = gross * rate / (100 + rate), rounded
+//
+//        ExtractTax(120.00, 20, 2, RoundHalfUp)     returns 20.00
+//        ExtractTax(100.00, 20, 2, RoundHalfUp)     returns 16.67             exact tax is 16.6666...
+//
+func ExtractTax(gross Quad, rate Quad, scale int32, rounding RoundingMode) (tax Quad, status Status) {
+
+	tax = gross.MulWithMode(rate, Round05Up).DivWithMode(hundred.Add(rate), Round05Up).Quantize(unitQuad(clampExp(-int64(scale))), rounding)
+
+	return tax, tax.Status()
+}
+
+// NetFromGross returns the net amount and the tax included in the gross amount.
+// The tax is computed by ExtractTax, and net is gross - tax, so that the amounts balance exactly.
+//
+//        NetFromGross(100.00, 20, 2, RoundHalfUp)     returns 83.33, 16.67
+//
+func NetFromGross(gross Quad, rate Quad, scale int32, rounding RoundingMode) (net Quad, tax Quad, status Status) {
+
+	tax, _ = ExtractTax(gross, rate, scale, rounding)
+	net = gross.Sub(tax)
+
+	return net, tax, net.Status()
+}
@@ -0,0 +1,101 @@
+package decnum
+
+import (
+	"testing"
+)
+
+func TestMulDivWithMode(t *testing.T) {
+
+	samples := []struct {
+		a        string
+		b        string
+		div      bool
+		rounding RoundingMode
+		expected string
+	}{
+		{"2", "3", true, RoundDown, "0.6666666666666666666666666666666666"},
+		{"2", "3", true, RoundUp, "0.6666666666666666666666666666666667"},
+		{"1", "3", true, Round05Up, "0.3333333333333333333333333333333333"},
+		{"1", "8", true, RoundDown, "0.125"},
+		{"1", "0", true, RoundDown, "Infinity"},
+		{"1234567890123456789", "1234567890123456789", false, RoundDown, "1.524157875323883675019051998750190E+36"},
+		{"1234567890123456789", "1234567890123456789", false, RoundUp, "1.524157875323883675019051998750191E+36"},
+		{"1.5", "2", false, RoundDown, "3.0"},
+	}
+
+	for _, s := range samples {
+		var r Quad
+		if s.div {
+			r = must_quad(s.a).DivWithMode(must_quad(s.b), s.rounding)
+		} else {
+			r = must_quad(s.a).MulWithMode(must_quad(s.b), s.rounding)
+		}
+		if r.String() != s.expected {
+			t.Fatalf("%s op %s with %s = %s, expected %s", s.a, s.b, s.rounding, r, s.expected)
+		}
+	}
+}
+
+func TestTax(t *testing.T) {
+
+	samples := []struct {
+		amount   string
+		rate     string
+		scale    int32
+		rounding RoundingMode
+		tax      string // AddTax on amount
+		gross    string
+		incl     string // ExtractTax on amount
+		net      string
+	}{
+		{"100.00", "20", 2, RoundHalfUp, "20.00", "120.00", "16.67", "83.33"},
+		{"120.00", "20", 2, RoundHalfUp, "24.00", "144.00", "20.00", "100.00"},
+		{"0.99", "20", 2, RoundHalfUp, "0.20", "1.19", "0.17", "0.82"},
+		{"0.99", "20", 2, RoundDown, "0.19", "1.18", "0.16", "0.83"},
+		{"10.5", "5", 0, RoundHalfUp, "1", "11.5", "1", "9.5"},
+		{"10.5", "5", 0, RoundHalfEven, "1", "11.5", "0", "10.5"},
+		{"10.5", "5", 0, RoundHalfDown, "1", "11.5", "0", "10.5"},
+		{"10.5", "5", 0, RoundCeiling, "1", "11.5", "1", "9.5"},
+		{"1000", "18", 2, RoundHalfUp, "180.00", "1180.00", "152.54", "847.46"},
+		{"1000", "7.7", 2, RoundHalfUp, "77.00", "1077.00", "71.49", "928.51"},
+		{"-100.00", "20", 2, RoundHalfUp, "-20.00", "-120.00", "-16.67", "-83.33"},
+		{"1000", "0", 2, RoundHalfUp, "0.00", "1000.00", "0.00", "1000.00"},
+		{"1000", "10", 0, Round05Up, "100", "1100", "91", "909"},
+	}
+
+	for _, s := range samples {
+		amount := must_quad(s.amount)
+		rate := must_quad(s.rate)
+
+		gross, tax, status := AddTax(amount, rate, s.scale, s.rounding)
+		if tax.String() != s.tax || gross.String() != s.gross || status&ErrorMask != 0 {
+			t.Fatalf("AddTax(%s, %s, %d, %s) = %s, %s, %s, expected %s, %s", s.amount, s.rate, s.scale, s.rounding, gross, tax, status, s.gross, s.tax)
+		}
+
+		incl, status := ExtractTax(amount, rate, s.scale, s.rounding)
+		if incl.String() != s.incl || status&ErrorMask != 0 {
+			t.Fatalf("ExtractTax(%s, %s, %d, %s) = %s, %s, expected %s", s.amount, s.rate, s.scale, s.rounding, incl, status, s.incl)
+		}
+
+		net, incl2, status := NetFromGross(amount, rate, s.scale, s.rounding)
+		if net.String() != s.net || !incl2.Equal(incl) || status&ErrorMask != 0 {
+			t.Fatalf("NetFromGross(%s, %s, %d, %s) = %s, %s, %s, expected %s, %s", s.amount, s.rate, s.scale, s.rounding, net, incl2, status, s.net, s.incl)
+		}
+
+		if !net.Add(incl2).Equal(amount) {
+			t.Fatalf("NetFromGross(%s, %s): %s + %s != gross", s.amount, s.rate, net, incl2)
+		}
+	}
+
+	if tax, status := ExtractTax(must_quad("100"), must_quad("-100"), 2, RoundHalfUp); !tax.IsNaN() || status&ErrorMask != DivisionByZero|InvalidOperation {
+		t.Fatalf("ExtractTax with rate -100 = %s, %s, expected NaN, DivisionByZero and InvalidOperation", tax, status)
+	}
+
+	if net, tax, status := NetFromGross(must_quad("100"), must_quad("-100"), 2, RoundHalfUp); !net.IsNaN() || !tax.IsNaN() || status&ErrorMask != DivisionByZero|InvalidOperation {
+		t.Fatalf("NetFromGross with rate -100 = %s, %s, %s, expected NaN, DivisionByZero and InvalidOperation", net, tax, status)
+	}
+
+	if _, status := ExtractTax(must_quad("100"), must_quad("20"), 2, RoundHalfUp); status&Inexact == 0 {
+		t.Fatalf("ExtractTax(100, 20) must set Inexact, got %s", status)
+	}
+}
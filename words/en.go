@@ -0,0 +1,48 @@
+package words
+
+import (
+	"strings"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                               English                                */
+/*                                                                      */
+/************************************************************************/
+
+var (
+	enUnits = [20]string{"", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+
+	enTens = [10]string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+
+	// enScales[i] is 1000^i, in short scale. enScales[0] is unused.
+	enScales = [12]string{"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion",
+		"sextillion", "septillion", "octillion", "nonillion", "decillion"}
+)
+
+// spellGroupEN writes n, in the range [1, 999], in English words, e.g. "one hundred twenty-three".
+//
+func spellGroupEN(sb *strings.Builder, n int) {
+
+	if n >= 100 {
+		sb.WriteString(enUnits[n/100])
+		sb.WriteString(" hundred")
+		n %= 100
+		if n == 0 {
+			return
+		}
+		sb.WriteByte(' ')
+	}
+
+	if n >= 20 {
+		sb.WriteString(enTens[n/10])
+		n %= 10
+		if n == 0 {
+			return
+		}
+		sb.WriteByte('-')
+	}
+
+	sb.WriteString(enUnits[n])
+}
@@ -0,0 +1,72 @@
+package words
+
+import (
+	"strings"
+)
+
+/************************************************************************/
+/*                                                                      */
+/*                               Russian                                */
+/*                                                                      */
+/************************************************************************/
+
+var (
+	ruUnits = [20]string{"", "один", "два", "три", "четыре", "пять", "шесть", "семь", "восемь", "девять",
+		"десять", "одиннадцать", "двенадцать", "тринадцать", "четырнадцать", "пятнадцать", "шестнадцать", "семнадцать", "восемнадцать", "девятнадцать"}
+
+	ruUnitsFeminine = [3]string{"", "одна", "две"}
+
+	ruTens = [10]string{"", "", "двадцать", "тридцать", "сорок", "пятьдесят", "шестьдесят", "семьдесят", "восемьдесят", "девяносто"}
+
+	ruHundreds = [10]string{"", "сто", "двести", "триста", "четыреста", "пятьсот", "шестьсот", "семьсот", "восемьсот", "девятьсот"}
+
+	// ruScales[i] is 1000^i. ruScales[0] is unused.
+	ruScales = [12]unit{
+		{},
+		{[3]string{"тысяча", "тысячи", "тысяч"}, feminine},
+		{[3]string{"миллион", "миллиона", "миллионов"}, masculine},
+		{[3]string{"миллиард", "миллиарда", "миллиардов"}, masculine},
+		{[3]string{"триллион", "триллиона", "триллионов"}, masculine},
+		{[3]string{"квадриллион", "квадриллиона", "квадриллионов"}, masculine},
+		{[3]string{"квинтиллион", "квинтиллиона", "квинтиллионов"}, masculine},
+		{[3]string{"секстиллион", "секстиллиона", "секстиллионов"}, masculine},
+		{[3]string{"септиллион", "септиллиона", "септиллионов"}, masculine},
+		{[3]string{"октиллион", "октиллиона", "октиллионов"}, masculine},
+		{[3]string{"нониллион", "нониллиона", "нониллионов"}, masculine},
+		{[3]string{"дециллион", "дециллиона", "дециллионов"}, masculine},
+	}
+)
+
+// spellGroupRU writes n, in the range [1, 999], in Russian words.
+// g is the gender of the counted noun, e.g. "одна тысяча", "двадцать две копейки".
+//
+func spellGroupRU(sb *strings.Builder, n int, g gender) {
+	var parts [3]string
+
+	words := parts[:0]
+
+	if n >= 100 {
+		words = append(words, ruHundreds[n/100])
+		n %= 100
+	}
+
+	if n >= 20 {
+		words = append(words, ruTens[n/10])
+		n %= 10
+	}
+
+	switch {
+	case n == 0:
+	case n <= 2 && g == feminine:
+		words = append(words, ruUnitsFeminine[n])
+	default:
+		words = append(words, ruUnits[n])
+	}
+
+	for i, w := range words {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(w)
+	}
+}
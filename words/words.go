@@ -0,0 +1,341 @@
+/*
+Package words spells out amounts in words, as required on invoices and payment orders ("сумма прописью").
+
+	a, _ := decnum.FromString("1234.05")
+
+	s, err := words.Spell(a, words.Options{Language: words.Russian, Currency: "RUB"})
+	// "одна тысяча двести тридцать четыре рубля 05 копеек"
+
+	s, err = words.Spell(a, words.Options{Language: words.English, Currency: "USD", MinorInWords: true})
+	// "one thousand two hundred thirty-four dollars and five cents"
+
+Russian words agree in gender and number with the unit: "одна тысяча", "две копейки", "двадцать один рубль", "пять миллионов".
+English uses the short scale: billion is 10^9.
+
+The integral part can have up to 36 digits, so that any integer Quad with 34 digits can be spelled out, up to decillions (10^33).
+*/
+package words
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rin01/decnum"
+)
+
+// Language of the words.
+//
+type Language int
+
+const (
+	Russian Language = iota
+	English
+)
+
+// Options configures Spell.
+//
+// The zero value spells out integers in Russian, without currency.
+//
+type Options struct {
+	Language     Language
+	Currency     string // ISO 4217 code of the currency: "RUB", "USD" or "EUR". Empty means no currency, and the amount must be an integer.
+	MinorInWords bool   // if true, the minor units are spelled out, e.g. "пять копеек". Else, they are written with 2 digits, e.g. "05 копеек".
+	Capitalize   bool   // if true, the first letter is upper case, e.g. "Сто рублей 00 копеек".
+}
+
+// Errors returned by Spell.
+//
+var (
+	ErrNotFinite       = errors.New("words: amount is NaN or Infinity")
+	ErrTooLarge        = errors.New("words: amount is too large to be spelled out")
+	ErrNotInteger      = errors.New("words: amount without currency must be an integer")
+	ErrMinorUnits      = errors.New("words: amount has more fractional digits than the minor unit of the currency")
+	ErrUnknownCurrency = errors.New("words: unknown currency")
+	ErrUnknownLanguage = errors.New("words: unknown language")
+)
+
+// gender of a Russian noun, for the agreement of "один", "одна", "два", "две".
+//
+type gender int
+
+const (
+	masculine gender = iota
+	feminine
+)
+
+// unit is a noun counted by a number.
+//
+// Russian forms are for 1, 2-4 and 5-20, e.g. "рубль", "рубля", "рублей".
+// English forms are singular and plural, the third form is unused.
+//
+type unit struct {
+	forms  [3]string
+	gender gender
+}
+
+// currency contains the major and minor units of a currency, for each language.
+//
+type currency struct {
+	minorDigits int32
+	major       [2]unit // indexed by Language
+	minor       [2]unit
+}
+
+var (
+	zeroWord  = [2]string{"ноль", "zero"}   // indexed by Language
+	minusWord = [2]string{"минус", "minus"} // indexed by Language
+
+	minorScale = [3]decnum.Quad{decnum.FromInt32(1), decnum.FromInt32(10), decnum.FromInt32(100)} // indexed by currency.minorDigits
+)
+
+var currencies = map[string]currency{
+	"RUB": {2,
+		[2]unit{{[3]string{"рубль", "рубля", "рублей"}, masculine}, {[3]string{"ruble", "rubles"}, masculine}},
+		[2]unit{{[3]string{"копейка", "копейки", "копеек"}, feminine}, {[3]string{"kopeck", "kopecks"}, masculine}},
+	},
+	"USD": {2,
+		[2]unit{{[3]string{"доллар", "доллара", "долларов"}, masculine}, {[3]string{"dollar", "dollars"}, masculine}},
+		[2]unit{{[3]string{"цент", "цента", "центов"}, masculine}, {[3]string{"cent", "cents"}, masculine}},
+	},
+	"EUR": {2,
+		[2]unit{{[3]string{"евро", "евро", "евро"}, masculine}, {[3]string{"euro", "euros"}, masculine}},
+		[2]unit{{[3]string{"цент", "цента", "центов"}, masculine}, {[3]string{"cent", "cents"}, masculine}},
+	},
+}
+
+// Spell returns the amount a in words.
+//
+// If opts.Currency is set, the amount is written with the major unit, and the minor units, e.g. "сто рублей 50 копеек".
+// The minor units are always written, even if 0. The amount must not have more fractional digits than the minor unit, so it must be rounded beforehand if needed, e.g. with a.RoundWithMode(2, decnum.RoundHalfUp).
+//
+// If opts.Currency is empty, a must be an integer.
+//
+// Negative amounts start with "минус" or "minus".
+//
+// The status field of a is not checked.
+//
+func Spell(a decnum.Quad, opts Options) (string, error) {
+	var (
+		sb       strings.Builder
+		cur      currency
+		hasCur   bool
+		intDigit []byte
+		minor    int32
+	)
+
+	if opts.Language != Russian && opts.Language != English {
+		return "", ErrUnknownLanguage
+	}
+
+	if !a.IsFinite() {
+		return "", ErrNotFinite
+	}
+
+	if opts.Currency != "" {
+		if cur, hasCur = currencies[opts.Currency]; !hasCur {
+			return "", ErrUnknownCurrency
+		}
+	}
+
+	// split the amount into integral part and minor units
+
+	intPart := a.Abs().ToIntegral(decnum.RoundDown)
+	fracPart := a.Abs().Sub(intPart)
+
+	if !fracPart.IsZero() {
+		if !hasCur {
+			return "", ErrNotInteger
+		}
+		scaled := fracPart.Mul(minorScale[cur.minorDigits])
+		truncated := scaled.ToIntegral(decnum.RoundDown)
+		if !truncated.Equal(scaled) {
+			return "", ErrMinorUnits
+		}
+		minor, _ = truncated.ToInt32(decnum.RoundDown)
+	}
+
+	intDigit = intPart.AppendFormat(nil, decnum.FormatPlain, 0)
+	if len(intDigit) > 3*len(ruScales) {
+		return "", ErrTooLarge
+	}
+
+	// write the words
+
+	if a.IsNegative() && (!intPart.IsZero() || minor != 0) {
+		sb.WriteString(minusWord[opts.Language])
+		sb.WriteByte(' ')
+	}
+
+	majorUnit := unit{gender: masculine}
+	if hasCur {
+		majorUnit = cur.major[opts.Language]
+	}
+
+	spellInteger(&sb, intDigit, opts.Language, majorUnit.gender)
+
+	if hasCur {
+		sb.WriteByte(' ')
+		sb.WriteString(majorUnit.forms[pluralForm(intDigit, opts.Language)])
+
+		if opts.Language == English {
+			sb.WriteString(" and")
+		}
+		sb.WriteByte(' ')
+
+		minorUnit := cur.minor[opts.Language]
+		minorDigit := appendPadded(nil, minor, cur.minorDigits)
+
+		if opts.MinorInWords {
+			spellInteger(&sb, minorDigit, opts.Language, minorUnit.gender)
+		} else {
+			sb.Write(minorDigit)
+		}
+
+		sb.WriteByte(' ')
+		sb.WriteString(minorUnit.forms[pluralForm(minorDigit, opts.Language)])
+	}
+
+	s := sb.String()
+
+	if opts.Capitalize {
+		r, size := utf8.DecodeRuneInString(s)
+		s = string(unicode.ToUpper(r)) + s[size:]
+	}
+
+	return s, nil
+}
+
+// spellInteger writes the integer in digits, which contains only ASCII digits, in words.
+// g is the gender of the counted unit, used by Russian "один"/"одна" and "два"/"две" in the last group.
+//
+func spellInteger(sb *strings.Builder, digits []byte, lang Language, g gender) {
+
+	for len(digits) > 1 && digits[0] == '0' {
+		digits = digits[1:]
+	}
+
+	if len(digits) == 1 && digits[0] == '0' {
+		sb.WriteString(zeroWord[lang])
+		return
+	}
+
+	// groups of 3 digits, from the highest scale
+
+	first := true
+	n := (len(digits) + 2) / 3
+
+	for scale := n - 1; scale >= 0; scale-- {
+		end := len(digits) - 3*scale
+		start := end - 3
+		if start < 0 {
+			start = 0
+		}
+
+		group := digits[start:end]
+		value := groupValue(group)
+		if value == 0 {
+			continue
+		}
+
+		if !first {
+			sb.WriteByte(' ')
+		}
+		first = false
+
+		switch lang {
+		case Russian:
+			groupGender := masculine
+			switch scale {
+			case 0:
+				groupGender = g
+			case 1:
+				groupGender = feminine // тысяча
+			}
+			spellGroupRU(sb, value, groupGender)
+			if scale > 0 {
+				sb.WriteByte(' ')
+				sb.WriteString(ruScales[scale].forms[pluralFormRU(value)])
+			}
+
+		default:
+			spellGroupEN(sb, value)
+			if scale > 0 {
+				sb.WriteByte(' ')
+				sb.WriteString(enScales[scale])
+			}
+		}
+	}
+}
+
+// groupValue returns the value of a group of up to 3 ASCII digits.
+//
+func groupValue(group []byte) int {
+	var v int
+
+	for _, c := range group {
+		v = v*10 + int(c-'0')
+	}
+	return v
+}
+
+// pluralForm returns the index of the form of a unit counted by the integer in digits.
+//
+func pluralForm(digits []byte, lang Language) int {
+
+	last := digits
+	if len(last) > 3 {
+		last = last[len(last)-3:]
+	}
+	value := groupValue(last)
+
+	if lang == Russian {
+		return pluralFormRU(value)
+	}
+
+	if value == 1 && allZeros(digits[:len(digits)-len(last)]) { // English singular only for exactly 1
+		return 0
+	}
+	return 1
+}
+
+// pluralFormRU returns the index of the Russian form for the number n: 0 for 1, 21, 31..., 1 for 2-4, 22-24..., 2 for 0, 5-20, 25-30...
+//
+func pluralFormRU(n int) int {
+
+	switch {
+	case n%100 >= 11 && n%100 <= 14:
+		return 2
+	case n%10 == 1:
+		return 0
+	case n%10 >= 2 && n%10 <= 4:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// allZeros returns true if digits contains only '0'.
+//
+func allZeros(digits []byte) bool {
+
+	for _, c := range digits {
+		if c != '0' {
+			return false
+		}
+	}
+	return true
+}
+
+// appendPadded appends v with exactly n digits, padded with leading zeros.
+//
+func appendPadded(dst []byte, v int32, n int32) []byte {
+	var buff [10]byte
+
+	for i := n - 1; i >= 0; i-- {
+		buff[i] = byte('0' + v%10)
+		v /= 10
+	}
+	return append(dst, buff[:n]...)
+}
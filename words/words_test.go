@@ -0,0 +1,130 @@
+package words
+
+import (
+	"testing"
+
+	"github.com/rin01/decnum"
+)
+
+func must_quad(s string) decnum.Quad {
+
+	q, err := decnum.FromString(s)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+func TestSpell(t *testing.T) {
+
+	rub := Options{Language: Russian, Currency: "RUB"}
+	rubWords := Options{Language: Russian, Currency: "RUB", MinorInWords: true}
+	usd := Options{Language: English, Currency: "USD"}
+	usdWords := Options{Language: English, Currency: "USD", MinorInWords: true}
+	eur := Options{Language: Russian, Currency: "EUR", MinorInWords: true}
+	ru := Options{Language: Russian}
+	en := Options{Language: English}
+
+	samples := []struct {
+		a        string
+		opts     Options
+		expected string
+	}{
+		{"0", ru, "ноль"},
+		{"1", ru, "один"},
+		{"12", ru, "двенадцать"},
+		{"21", ru, "двадцать один"},
+		{"100", ru, "сто"},
+		{"1000", ru, "одна тысяча"},
+		{"2000", ru, "две тысячи"},
+		{"5000", ru, "пять тысяч"},
+		{"11000", ru, "одиннадцать тысяч"},
+		{"21000", ru, "двадцать одна тысяча"},
+		{"1000000", ru, "один миллион"},
+		{"2000000", ru, "два миллиона"},
+		{"1002003", ru, "один миллион две тысячи три"},
+		{"999999999", ru, "девятьсот девяносто девять миллионов девятьсот девяносто девять тысяч девятьсот девяносто девять"},
+		{"1E+33", ru, "один дециллион"},
+		{"-15", ru, "минус пятнадцать"},
+
+		{"0", en, "zero"},
+		{"15", en, "fifteen"},
+		{"42", en, "forty-two"},
+		{"100", en, "one hundred"},
+		{"101", en, "one hundred one"},
+		{"1234567", en, "one million two hundred thirty-four thousand five hundred sixty-seven"},
+		{"1E+9", en, "one billion"},
+		{"999E+33", en, "nine hundred ninety-nine decillion"},
+
+		{"1234.05", rub, "одна тысяча двести тридцать четыре рубля 05 копеек"},
+		{"1.01", rub, "один рубль 01 копейка"},
+		{"2.02", rub, "два рубля 02 копейки"},
+		{"11.11", rub, "одиннадцать рублей 11 копеек"},
+		{"21.21", rub, "двадцать один рубль 21 копейка"},
+		{"100", rub, "сто рублей 00 копеек"},
+		{"0.5", rub, "ноль рублей 50 копеек"},
+		{"-0.5", rub, "минус ноль рублей 50 копеек"},
+		{"-0.00", rub, "ноль рублей 00 копеек"},
+		{"1.01", rubWords, "один рубль одна копейка"},
+		{"2.22", rubWords, "два рубля двадцать две копейки"},
+		{"5", rubWords, "пять рублей ноль копеек"},
+		{"2001.12", rubWords, "две тысячи один рубль двенадцать копеек"},
+		{"21.01", eur, "двадцать один евро один цент"},
+		{"3.02", eur, "три евро два цента"},
+
+		{"1234.05", usd, "one thousand two hundred thirty-four dollars and 05 cents"},
+		{"1234.05", usdWords, "one thousand two hundred thirty-four dollars and five cents"},
+		{"1.01", usdWords, "one dollar and one cent"},
+		{"1001", usd, "one thousand one dollars and 00 cents"},
+		{"0.01", usd, "zero dollars and 01 cent"},
+		{"-2.5", usdWords, "minus two dollars and fifty cents"},
+		{"1", Options{Language: English, Currency: "EUR"}, "one euro and 00 cents"},
+		{"3", Options{Language: English, Currency: "RUB"}, "three rubles and 00 kopecks"},
+
+		{"1234.50", Options{Language: Russian, Currency: "RUB", Capitalize: true}, "Одна тысяча двести тридцать четыре рубля 50 копеек"},
+		{"7", Options{Language: English, Capitalize: true}, "Seven"},
+
+		{"9999999999999999999999999999999999", ru, "девять дециллионов девятьсот девяносто девять нониллионов девятьсот девяносто девять октиллионов " +
+			"девятьсот девяносто девять септиллионов девятьсот девяносто девять секстиллионов девятьсот девяносто девять квинтиллионов " +
+			"девятьсот девяносто девять квадриллионов девятьсот девяносто девять триллионов девятьсот девяносто девять миллиардов " +
+			"девятьсот девяносто девять миллионов девятьсот девяносто девять тысяч девятьсот девяносто девять"},
+		{"99999999999999999999999999999999.99", rub, "девяносто девять нониллионов девятьсот девяносто девять октиллионов " +
+			"девятьсот девяносто девять септиллионов девятьсот девяносто девять секстиллионов девятьсот девяносто девять квинтиллионов " +
+			"девятьсот девяносто девять квадриллионов девятьсот девяносто девять триллионов девятьсот девяносто девять миллиардов " +
+			"девятьсот девяносто девять миллионов девятьсот девяносто девять тысяч девятьсот девяносто девять рублей 99 копеек"},
+	}
+
+	for _, s := range samples {
+		r, err := Spell(must_quad(s.a), s.opts)
+		if err != nil {
+			t.Fatalf("Spell(%s, %+v) returned error %v", s.a, s.opts, err)
+		}
+		if r != s.expected {
+			t.Fatalf("Spell(%s, %+v) = %q, expected %q", s.a, s.opts, r, s.expected)
+		}
+	}
+}
+
+func TestSpellError(t *testing.T) {
+
+	samples := []struct {
+		a        string
+		opts     Options
+		expected error
+	}{
+		{"NaN", Options{}, ErrNotFinite},
+		{"-Inf", Options{Currency: "RUB"}, ErrNotFinite},
+		{"1E+36", Options{}, ErrTooLarge},
+		{"1.5", Options{}, ErrNotInteger},
+		{"1.005", Options{Currency: "RUB"}, ErrMinorUnits},
+		{"1", Options{Currency: "XXX"}, ErrUnknownCurrency},
+		{"1", Options{Language: Language(5)}, ErrUnknownLanguage},
+	}
+
+	for _, s := range samples {
+		_, err := Spell(must_quad(s.a), s.opts)
+		if err != s.expected {
+			t.Fatalf("Spell(%s, %+v) returned error %v, expected %v", s.a, s.opts, err, s.expected)
+		}
+	}
+}